@@ -0,0 +1,248 @@
+package mathg
+
+/*
+MatrixOrder selects the element order used by ToArray and FromArray.
+RowMajor lists the first row first; ColumnMajor lists the first column first
+and matches the field order of the matrix structs and OpenGL.
+*/
+type MatrixOrder int
+
+const (
+	RowMajor MatrixOrder = iota
+	ColumnMajor
+)
+
+func (m *Mat2) elements() [2][2]*float64 {
+	return [2][2]*float64{
+		{&m.M11, &m.M12},
+		{&m.M21, &m.M22},
+	}
+}
+
+func (m *Mat3) elements() [3][3]*float64 {
+	return [3][3]*float64{
+		{&m.M11, &m.M12, &m.M13},
+		{&m.M21, &m.M22, &m.M23},
+		{&m.M31, &m.M32, &m.M33},
+	}
+}
+
+func (m *Mat4) elements() [4][4]*float64 {
+	return [4][4]*float64{
+		{&m.M11, &m.M12, &m.M13, &m.M14},
+		{&m.M21, &m.M22, &m.M23, &m.M24},
+		{&m.M31, &m.M32, &m.M33, &m.M34},
+		{&m.M41, &m.M42, &m.M43, &m.M44},
+	}
+}
+
+func (m *Mat2) At(row, col int) float64 {
+	return *m.elements()[row][col]
+}
+
+func (m *Mat2) Set(row, col int, v float64) {
+	*m.elements()[row][col] = v
+}
+
+func (m *Mat2) Row(i int) *Vec2 {
+	e := m.elements()
+	return &Vec2{*e[i][0], *e[i][1]}
+}
+
+func (m *Mat2) Col(i int) *Vec2 {
+	e := m.elements()
+	return &Vec2{*e[0][i], *e[1][i]}
+}
+
+func (m *Mat2) SetRow(i int, v *Vec2) {
+	e := m.elements()
+	*e[i][0], *e[i][1] = v.X, v.Y
+}
+
+func (m *Mat2) SetCol(i int, v *Vec2) {
+	e := m.elements()
+	*e[0][i], *e[1][i] = v.X, v.Y
+}
+
+func (m *Mat2) FromRows(r0, r1 *Vec2) *Mat2 {
+	return &Mat2{r0.X, r1.X, r0.Y, r1.Y}
+}
+
+func (m *Mat2) FromCols(c0, c1 *Vec2) *Mat2 {
+	return &Mat2{c0.X, c0.Y, c1.X, c1.Y}
+}
+
+func (m *Mat2) ToArray(order MatrixOrder) [4]float64 {
+	if order == ColumnMajor {
+		return [4]float64{m.M11, m.M21, m.M12, m.M22}
+	}
+	return [4]float64{m.M11, m.M12, m.M21, m.M22}
+}
+
+func (m *Mat2) FromArray(a [4]float64, order MatrixOrder) *Mat2 {
+	if order == ColumnMajor {
+		return &Mat2{a[0], a[1], a[2], a[3]}
+	}
+	return &Mat2{a[0], a[2], a[1], a[3]}
+}
+
+func (m *Mat3) At(row, col int) float64 {
+	return *m.elements()[row][col]
+}
+
+func (m *Mat3) Set(row, col int, v float64) {
+	*m.elements()[row][col] = v
+}
+
+func (m *Mat3) Row(i int) *Vec3 {
+	e := m.elements()
+	return &Vec3{*e[i][0], *e[i][1], *e[i][2]}
+}
+
+func (m *Mat3) Col(i int) *Vec3 {
+	e := m.elements()
+	return &Vec3{*e[0][i], *e[1][i], *e[2][i]}
+}
+
+func (m *Mat3) SetRow(i int, v *Vec3) {
+	e := m.elements()
+	*e[i][0], *e[i][1], *e[i][2] = v.X, v.Y, v.Z
+}
+
+func (m *Mat3) SetCol(i int, v *Vec3) {
+	e := m.elements()
+	*e[0][i], *e[1][i], *e[2][i] = v.X, v.Y, v.Z
+}
+
+func (m *Mat3) FromRows(r0, r1, r2 *Vec3) *Mat3 {
+	return &Mat3{
+		r0.X, r1.X, r2.X,
+		r0.Y, r1.Y, r2.Y,
+		r0.Z, r1.Z, r2.Z,
+	}
+}
+
+func (m *Mat3) FromCols(c0, c1, c2 *Vec3) *Mat3 {
+	return &Mat3{
+		c0.X, c0.Y, c0.Z,
+		c1.X, c1.Y, c1.Z,
+		c2.X, c2.Y, c2.Z,
+	}
+}
+
+func (m *Mat3) ToArray(order MatrixOrder) [9]float64 {
+	if order == ColumnMajor {
+		return [9]float64{m.M11, m.M21, m.M31, m.M12, m.M22, m.M32, m.M13, m.M23, m.M33}
+	}
+	return [9]float64{m.M11, m.M12, m.M13, m.M21, m.M22, m.M23, m.M31, m.M32, m.M33}
+}
+
+func (m *Mat3) FromArray(a [9]float64, order MatrixOrder) *Mat3 {
+	if order == ColumnMajor {
+		return &Mat3{a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7], a[8]}
+	}
+	return &Mat3{a[0], a[3], a[6], a[1], a[4], a[7], a[2], a[5], a[8]}
+}
+
+/*
+ToMat4 embeds m as the upper 3x3 of an otherwise identity Mat4.
+*/
+func (m *Mat3) ToMat4() *Mat4 {
+	return &Mat4{
+		m.M11, m.M21, m.M31, 0.,
+		m.M12, m.M22, m.M32, 0.,
+		m.M13, m.M23, m.M33, 0.,
+		0., 0., 0., 1.,
+	}
+}
+
+func (m *Mat4) At(row, col int) float64 {
+	return *m.elements()[row][col]
+}
+
+func (m *Mat4) Set(row, col int, v float64) {
+	*m.elements()[row][col] = v
+}
+
+func (m *Mat4) Row(i int) *Vec4 {
+	e := m.elements()
+	return &Vec4{*e[i][0], *e[i][1], *e[i][2], *e[i][3]}
+}
+
+func (m *Mat4) Col(i int) *Vec4 {
+	e := m.elements()
+	return &Vec4{*e[0][i], *e[1][i], *e[2][i], *e[3][i]}
+}
+
+func (m *Mat4) SetRow(i int, v *Vec4) {
+	e := m.elements()
+	*e[i][0], *e[i][1], *e[i][2], *e[i][3] = v.X, v.Y, v.Z, v.W
+}
+
+func (m *Mat4) SetCol(i int, v *Vec4) {
+	e := m.elements()
+	*e[0][i], *e[1][i], *e[2][i], *e[3][i] = v.X, v.Y, v.Z, v.W
+}
+
+func (m *Mat4) FromRows(r0, r1, r2, r3 *Vec4) *Mat4 {
+	return &Mat4{
+		r0.X, r1.X, r2.X, r3.X,
+		r0.Y, r1.Y, r2.Y, r3.Y,
+		r0.Z, r1.Z, r2.Z, r3.Z,
+		r0.W, r1.W, r2.W, r3.W,
+	}
+}
+
+func (m *Mat4) FromCols(c0, c1, c2, c3 *Vec4) *Mat4 {
+	return &Mat4{
+		c0.X, c0.Y, c0.Z, c0.W,
+		c1.X, c1.Y, c1.Z, c1.W,
+		c2.X, c2.Y, c2.Z, c2.W,
+		c3.X, c3.Y, c3.Z, c3.W,
+	}
+}
+
+func (m *Mat4) ToArray(order MatrixOrder) [16]float64 {
+	if order == ColumnMajor {
+		return [16]float64{
+			m.M11, m.M21, m.M31, m.M41,
+			m.M12, m.M22, m.M32, m.M42,
+			m.M13, m.M23, m.M33, m.M43,
+			m.M14, m.M24, m.M34, m.M44,
+		}
+	}
+	return [16]float64{
+		m.M11, m.M12, m.M13, m.M14,
+		m.M21, m.M22, m.M23, m.M24,
+		m.M31, m.M32, m.M33, m.M34,
+		m.M41, m.M42, m.M43, m.M44,
+	}
+}
+
+func (m *Mat4) FromArray(a [16]float64, order MatrixOrder) *Mat4 {
+	if order == ColumnMajor {
+		return &Mat4{
+			a[0], a[1], a[2], a[3],
+			a[4], a[5], a[6], a[7],
+			a[8], a[9], a[10], a[11],
+			a[12], a[13], a[14], a[15],
+		}
+	}
+	return &Mat4{
+		a[0], a[4], a[8], a[12],
+		a[1], a[5], a[9], a[13],
+		a[2], a[6], a[10], a[14],
+		a[3], a[7], a[11], a[15],
+	}
+}
+
+/*
+Mat3 returns the upper left 3x3 of m, its rotation and scale part.
+*/
+func (m *Mat4) Mat3() *Mat3 {
+	return &Mat3{
+		m.M11, m.M21, m.M31,
+		m.M12, m.M22, m.M32,
+		m.M13, m.M23, m.M33,
+	}
+}
@@ -0,0 +1,53 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestMat4At(t *testing.T) {
+	m := (&mathg.Mat4{}).Identity().Translation(&mathg.Vec3{1., 2., 3.})
+	if m.At(0, 3) != 1. || m.At(1, 3) != 2. || m.At(2, 3) != 3. || m.At(3, 0) != 0. {
+		t.Fatal("Mat4 At does not index row, column")
+	}
+	m.Set(3, 0, 5.)
+	if m.M41 != 5. {
+		t.Fatal("Mat4 Set wrote the wrong element")
+	}
+	c := m.Col(3)
+	if *c != (mathg.Vec4{1., 2., 3., 1.}) {
+		t.Fatalf("Mat4 Col = %v", *c)
+	}
+}
+
+func TestMat3RowsCols(t *testing.T) {
+	r0, r1, r2 := &mathg.Vec3{1., 2., 3.}, &mathg.Vec3{4., 5., 6.}, &mathg.Vec3{7., 8., 9.}
+	m := (&mathg.Mat3{}).FromRows(r0, r1, r2)
+	if *m.Row(1) != *r1 || *m.Col(2) != (mathg.Vec3{3., 6., 9.}) {
+		t.Fatal("Mat3 FromRows does not match Row and Col")
+	}
+	if *m != *(&mathg.Mat3{}).FromCols(m.Col(0), m.Col(1), m.Col(2)) {
+		t.Fatal("Mat3 FromCols round trip failed")
+	}
+	m.SetCol(0, &mathg.Vec3{-1., -2., -3.})
+	if m.M11 != -1. || m.M21 != -2. || m.M31 != -3. {
+		t.Fatal("Mat3 SetCol wrote the wrong elements")
+	}
+}
+
+func TestMatArrayOrder(t *testing.T) {
+	m := &mathg.Mat2{1., 2., 3., 4.}
+	if m.ToArray(mathg.RowMajor) != [4]float64{1., 3., 2., 4.} || m.ToArray(mathg.ColumnMajor) != [4]float64{1., 2., 3., 4.} {
+		t.Fatal("Mat2 ToArray order is wrong")
+	}
+	m4 := &mathg.Mat4{1., 2., 3., 4., 5., 6., 7., 8., 9., 10., 11., 12., 13., 14., 15., 16.}
+	for _, order := range []mathg.MatrixOrder{mathg.RowMajor, mathg.ColumnMajor} {
+		if *(&mathg.Mat4{}).FromArray(m4.ToArray(order), order) != *m4 {
+			t.Fatalf("Mat4 array round trip failed for order %d", order)
+		}
+	}
+	if *m4.Mat3().ToMat4().Mat3() != *m4.Mat3() {
+		t.Fatal("Mat3 embedding round trip failed")
+	}
+}
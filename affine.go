@@ -0,0 +1,190 @@
+package mathg
+
+/*
+Affine2 is a 2x3 matrix holding a 2D linear part and a translation in the
+third column. The implied bottom row is 0 0 1.
+
+m11 m12 m13
+m21 m22 m23
+*/
+type Affine2 struct {
+	M11 float64
+	M21 float64
+	M12 float64
+	M22 float64
+	M13 float64
+	M23 float64
+}
+
+/*
+Affine3 is a 3x4 matrix holding a 3D linear part and a translation in the
+fourth column. The implied bottom row is 0 0 0 1.
+
+m11 m12 m13 m14
+m21 m22 m23 m24
+m31 m32 m33 m34
+*/
+type Affine3 struct {
+	M11 float64
+	M21 float64
+	M31 float64
+	M12 float64
+	M22 float64
+	M32 float64
+	M13 float64
+	M23 float64
+	M33 float64
+	M14 float64
+	M24 float64
+	M34 float64
+}
+
+func (a *Affine2) Identity() *Affine2 {
+	return &Affine2{1., 0., 0., 1., 0., 0.}
+}
+
+func (a *Affine2) Determinant() float64 {
+	return a.M11*a.M22 - a.M12*a.M21
+}
+
+func (a *Affine2) Multiply(a1 *Affine2) *Affine2 {
+	return &Affine2{
+		a.M11*a1.M11 + a.M12*a1.M21,
+		a.M21*a1.M11 + a.M22*a1.M21,
+		a.M11*a1.M12 + a.M12*a1.M22,
+		a.M21*a1.M12 + a.M22*a1.M22,
+		a.M11*a1.M13 + a.M12*a1.M23 + a.M13,
+		a.M21*a1.M13 + a.M22*a1.M23 + a.M23,
+	}
+}
+
+func (a *Affine2) Inverse() *Affine2 {
+	d := 1. / a.Determinant()
+	m11 := a.M22 * d
+	m21 := -a.M21 * d
+	m12 := -a.M12 * d
+	m22 := a.M11 * d
+	return &Affine2{
+		m11, m21,
+		m12, m22,
+		-(m11*a.M13 + m12*a.M23), -(m21*a.M13 + m22*a.M23),
+	}
+}
+
+func (a *Affine2) TransformPoint(v *Vec2) *Vec2 {
+	return &Vec2{a.M11*v.X + a.M12*v.Y + a.M13, a.M21*v.X + a.M22*v.Y + a.M23}
+}
+
+func (a *Affine2) TransformDirection(v *Vec2) *Vec2 {
+	return &Vec2{a.M11*v.X + a.M12*v.Y, a.M21*v.X + a.M22*v.Y}
+}
+
+func (a *Affine2) Mat2() *Mat2 {
+	return &Mat2{a.M11, a.M21, a.M12, a.M22}
+}
+
+func (a *Affine2) ToMat3() *Mat3 {
+	return &Mat3{
+		a.M11, a.M21, 0.,
+		a.M12, a.M22, 0.,
+		a.M13, a.M23, 1.,
+	}
+}
+
+/*
+ToAffine2 drops the bottom row, which must be 0 0 1 for the result to match.
+*/
+func (m *Mat3) ToAffine2() *Affine2 {
+	return &Affine2{m.M11, m.M21, m.M12, m.M22, m.M13, m.M23}
+}
+
+func (a *Affine3) Identity() *Affine3 {
+	return &Affine3{
+		1., 0., 0.,
+		0., 1., 0.,
+		0., 0., 1.,
+		0., 0., 0.,
+	}
+}
+
+func (a *Affine3) Mat3() *Mat3 {
+	return &Mat3{
+		a.M11, a.M21, a.M31,
+		a.M12, a.M22, a.M32,
+		a.M13, a.M23, a.M33,
+	}
+}
+
+func (a *Affine3) Determinant() float64 {
+	return a.Mat3().Determinant()
+}
+
+func (a *Affine3) Multiply(a1 *Affine3) *Affine3 {
+	return &Affine3{
+		a.M11*a1.M11 + a.M12*a1.M21 + a.M13*a1.M31,
+		a.M21*a1.M11 + a.M22*a1.M21 + a.M23*a1.M31,
+		a.M31*a1.M11 + a.M32*a1.M21 + a.M33*a1.M31,
+		a.M11*a1.M12 + a.M12*a1.M22 + a.M13*a1.M32,
+		a.M21*a1.M12 + a.M22*a1.M22 + a.M23*a1.M32,
+		a.M31*a1.M12 + a.M32*a1.M22 + a.M33*a1.M32,
+		a.M11*a1.M13 + a.M12*a1.M23 + a.M13*a1.M33,
+		a.M21*a1.M13 + a.M22*a1.M23 + a.M23*a1.M33,
+		a.M31*a1.M13 + a.M32*a1.M23 + a.M33*a1.M33,
+		a.M11*a1.M14 + a.M12*a1.M24 + a.M13*a1.M34 + a.M14,
+		a.M21*a1.M14 + a.M22*a1.M24 + a.M23*a1.M34 + a.M24,
+		a.M31*a1.M14 + a.M32*a1.M24 + a.M33*a1.M34 + a.M34,
+	}
+}
+
+/*
+Inverse inverts the 3x3 linear part and applies it to the negated
+translation, which is much cheaper than a full Mat4 inverse.
+*/
+func (a *Affine3) Inverse() *Affine3 {
+	l := a.Mat3().Inverse()
+	t := (&Vec3{-a.M14, -a.M24, -a.M34}).MultiplyMat3(l)
+	return &Affine3{
+		l.M11, l.M21, l.M31,
+		l.M12, l.M22, l.M32,
+		l.M13, l.M23, l.M33,
+		t.X, t.Y, t.Z,
+	}
+}
+
+func (a *Affine3) TransformPoint(v *Vec3) *Vec3 {
+	return &Vec3{
+		a.M11*v.X + a.M12*v.Y + a.M13*v.Z + a.M14,
+		a.M21*v.X + a.M22*v.Y + a.M23*v.Z + a.M24,
+		a.M31*v.X + a.M32*v.Y + a.M33*v.Z + a.M34,
+	}
+}
+
+func (a *Affine3) TransformDirection(v *Vec3) *Vec3 {
+	return &Vec3{
+		a.M11*v.X + a.M12*v.Y + a.M13*v.Z,
+		a.M21*v.X + a.M22*v.Y + a.M23*v.Z,
+		a.M31*v.X + a.M32*v.Y + a.M33*v.Z,
+	}
+}
+
+func (a *Affine3) ToMat4() *Mat4 {
+	return &Mat4{
+		a.M11, a.M21, a.M31, 0.,
+		a.M12, a.M22, a.M32, 0.,
+		a.M13, a.M23, a.M33, 0.,
+		a.M14, a.M24, a.M34, 1.,
+	}
+}
+
+/*
+ToAffine3 drops the bottom row, which must be 0 0 0 1 for the result to
+match.
+*/
+func (m *Mat4) ToAffine3() *Affine3 {
+	return &Affine3{
+		m.M11, m.M21, m.M31,
+		m.M12, m.M22, m.M32,
+		m.M13, m.M23, m.M33,
+		m.M14, m.M24, m.M34,
+	}
+}
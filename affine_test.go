@@ -0,0 +1,53 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestAffine3MatchesMat4(t *testing.T) {
+	a := (&mathg.Transform{
+		Translation: mathg.Vec3{1., -2., 3.},
+		Rotation:    *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.3, 0.5, -1.2}, mathg.EulerXYZ),
+		Scale:       mathg.Vec3{2., 0.5, -1.5},
+	}).ToMat4()
+	b := (&mathg.Transform{
+		Translation: mathg.Vec3{-0.5, 4., 0.2},
+		Rotation:    *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{-0.9, 0.1, 0.6}, mathg.EulerZYX),
+		Scale:       mathg.Vec3{1., 3., 0.7},
+	}).ToMat4()
+	if !mat4NearlyEqual(a.ToAffine3().Multiply(b.ToAffine3()).ToMat4(), a.Multiply(b)) {
+		t.Fatal("Affine3.Multiply disagrees with Mat4.Multiply")
+	}
+	if !mat4NearlyEqual(a.ToAffine3().Inverse().ToMat4(), a.Inverse()) {
+		t.Fatal("Affine3.Inverse disagrees with Mat4.Inverse")
+	}
+	if !mathg.NearlyEqual(a.ToAffine3().Determinant(), a.Determinant(), tolerance) {
+		t.Fatal("Affine3.Determinant disagrees with Mat4.Determinant")
+	}
+	p := &mathg.Vec4{0.3, -1., 2., 1.}
+	q := p.MultiplyMat4(a)
+	d := (&mathg.Vec4{p.X, p.Y, p.Z, 0.}).MultiplyMat4(a)
+	if !vec3NearlyEqual(a.ToAffine3().TransformPoint(&mathg.Vec3{p.X, p.Y, p.Z}), &mathg.Vec3{q.X, q.Y, q.Z}) ||
+		!vec3NearlyEqual(a.ToAffine3().TransformDirection(&mathg.Vec3{p.X, p.Y, p.Z}), &mathg.Vec3{d.X, d.Y, d.Z}) {
+		t.Fatal("Affine3 transforms disagree with Mat4")
+	}
+}
+
+func TestAffine2MatchesMat3(t *testing.T) {
+	a := &mathg.Mat3{1.5, -0.4, 0., 0.7, 2., 0., 3., -1., 1.}
+	b := &mathg.Mat3{0.2, 1.1, 0., -0.8, 0.5, 0., -2., 0.5, 1.}
+	if !mat3NearlyEqual(a.ToAffine2().Multiply(b.ToAffine2()).ToMat3(), a.Multiply(b)) {
+		t.Fatal("Affine2.Multiply disagrees with Mat3.Multiply")
+	}
+	if !mat3NearlyEqual(a.ToAffine2().Inverse().ToMat3(), a.Inverse()) {
+		t.Fatal("Affine2.Inverse disagrees with Mat3.Inverse")
+	}
+	p := &mathg.Vec2{0.4, -1.5}
+	q := a.ToAffine2().TransformPoint(p)
+	r := (&mathg.Vec3{p.X, p.Y, 1.}).MultiplyMat3(a)
+	if !vec3NearlyEqual(&mathg.Vec3{q.X, q.Y, 1.}, r) {
+		t.Fatalf("Affine2.TransformPoint = %v, want %v", *q, *r)
+	}
+}
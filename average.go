@@ -0,0 +1,83 @@
+package mathg
+
+// Empty weights mean equal weights, anything else needs one weight per input.
+func checkWeights(name string, n int, weights []float64) {
+	if len(weights) != 0 && len(weights) != n {
+		panic("mathg: " + name + " needs one weight per input")
+	}
+}
+
+func weightAt(weights []float64, i int) float64 {
+	if len(weights) == 0 {
+		return 1.
+	}
+	return weights[i]
+}
+
+/*
+AverageQuaternions returns the weighted mean rotation of qs using Markley's
+method: the eigenvector with the largest eigenvalue of the sum of weighted
+outer products. The result is insensitive to the sign of each input and is
+returned in the hemisphere of qs[0]. weights may be nil for equal weights,
+otherwise it must have the same length as qs or the function panics.
+*/
+func AverageQuaternions(qs []*Quaternion, weights []float64) *Quaternion {
+	checkWeights("AverageQuaternions", len(qs), weights)
+	if len(qs) == 0 {
+		return &Quaternion{0., 0., 0., 1.}
+	}
+	a := make([][]float64, 4)
+	for i := range a {
+		a[i] = make([]float64, 4)
+	}
+	for i, q := range qs {
+		w := weightAt(weights, i)
+		v := [4]float64{q.X, q.Y, q.Z, q.W}
+		for r := 0; r < 4; r++ {
+			for c := 0; c < 4; c++ {
+				a[r][c] += w * v[r] * v[c]
+			}
+		}
+	}
+	values, vectors := jacobiEigenSymmetric(a)
+	best := 0
+	for i := 1; i < 4; i++ {
+		if values[i] > values[best] {
+			best = i
+		}
+	}
+	avg := &Quaternion{vectors[0][best], vectors[1][best], vectors[2][best], vectors[3][best]}
+	avg = avg.Normalize()
+	if avg.Dot(qs[0]) < 0. {
+		avg = avg.Negative()
+	}
+	return avg
+}
+
+/*
+AverageQuaternionsFast approximates AverageQuaternions by normalizing the
+weighted sum of the inputs after flipping them onto the hemisphere of qs[0].
+It is only accurate when the rotations are close to each other. weights
+follows the same rules as for AverageQuaternions.
+*/
+func AverageQuaternionsFast(qs []*Quaternion, weights []float64) *Quaternion {
+	checkWeights("AverageQuaternionsFast", len(qs), weights)
+	if len(qs) == 0 {
+		return &Quaternion{0., 0., 0., 1.}
+	}
+	sum := &Quaternion{}
+	for i, q := range qs {
+		w := weightAt(weights, i)
+		if q.Dot(qs[0]) < 0. {
+			w = -w
+		}
+		sum.X += q.X * w
+		sum.Y += q.Y * w
+		sum.Z += q.Z * w
+		sum.W += q.W * w
+	}
+	if sum.LengthSquared() < epsilon {
+		return &Quaternion{0., 0., 0., 1.}
+	}
+	return sum.Normalize()
+}
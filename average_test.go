@@ -0,0 +1,60 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestAverageQuaternionsSameAxis(t *testing.T) {
+	axis := &mathg.Vec4{0., 0., 1., 0.}
+	qs := []*mathg.Quaternion{
+		axis.ToQuaternionFromAxisAngle(0.2),
+		axis.ToQuaternionFromAxisAngle(0.6).Negative(),
+	}
+	want := axis.ToQuaternionFromAxisAngle(0.4)
+	avg := mathg.AverageQuaternions(qs, nil)
+	if !quaternionNearlyEqual(avg, want) {
+		t.Fatalf("AverageQuaternions = %v, want %v", *avg, *want)
+	}
+	fast := mathg.AverageQuaternionsFast(qs, nil)
+	if !quaternionNearlyEqual(fast, want) {
+		t.Fatalf("AverageQuaternionsFast = %v, want %v", *fast, *want)
+	}
+}
+
+func TestAverageQuaternionsWeights(t *testing.T) {
+	qs := []*mathg.Quaternion{
+		(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.1, 0.2, 0.3}, mathg.EulerXYZ),
+		(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{1.1, -0.2, 2.3}, mathg.EulerXYZ),
+	}
+	avg := mathg.AverageQuaternions(qs, []float64{1., 0.})
+	if !quaternionNearlyEqual(avg, qs[0]) {
+		t.Fatalf("AverageQuaternions with zero weight = %v, want %v", *avg, *qs[0])
+	}
+	if !mathg.NearlyEqual(avg.Magnitude(), 1., tolerance) || math.IsNaN(avg.W) {
+		t.Fatal("AverageQuaternions returned a non unit quaternion")
+	}
+}
+
+func TestAverageQuaternionsWeightCount(t *testing.T) {
+	qs := []*mathg.Quaternion{{0., 0., 0., 1.}, {0., 0., 0., 1.}}
+	for _, f := range []func(){
+		func() { mathg.AverageQuaternions(qs, []float64{1.}) },
+		func() { mathg.AverageQuaternions(qs, []float64{1., 1., 1.}) },
+		func() { mathg.AverageQuaternionsFast(qs, []float64{1.}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("averaging accepted the wrong number of weights")
+				}
+			}()
+			f()
+		}()
+	}
+	if avg := mathg.AverageQuaternions(qs, []float64{}); !quaternionNearlyEqual(avg, qs[0]) {
+		t.Fatalf("AverageQuaternions with empty weights = %v", *avg)
+	}
+}
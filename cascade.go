@@ -0,0 +1,117 @@
+package mathg
+
+import "math"
+
+/*
+CascadeSplits divides the view depth range near to far into count slices and
+returns the count+1 boundaries. lambda blends between uniform (0) and
+logarithmic (1) splits, the practical split scheme of Zhang et al.
+*/
+func CascadeSplits(near, far float64, count int, lambda float64) []float64 {
+	s := make([]float64, count+1)
+	for i := range s {
+		f := float64(i) / float64(count)
+		log := near * math.Pow(far/near, f)
+		uniform := near + (far-near)*f
+		s[i] = lambda*log + (1.-lambda)*uniform
+	}
+	s[0], s[count] = near, far
+	return s
+}
+
+/*
+FrustumSliceCorners returns the world space corners of the part of the
+camera frustum between the view depths sliceNear and sliceFar, in the order
+of ViewFrustum.Corners. The slice may extend past the far plane of proj,
+which may also be infinite.
+*/
+func (c ClipSpace) FrustumSliceCorners(view, proj *Mat4, sliceNear, sliceFar float64) [8]Vec3 {
+	var corners [8]Vec3
+	invProj := proj.Inverse()
+	invView := view.Inverse()
+	s := c.forward()
+	zn, zf := c.depthRange()
+	// The middle of the depth range is finite even for an infinite far plane.
+	zm := 0.5 * (zn + zf)
+	for i, p := range [4][2]float64{{-1., -1.}, {1., -1.}, {-1., 1.}, {1., 1.}} {
+		a := invProj.TransformPoint(&Vec3{p[0], p[1], zn})
+		b := invProj.TransformPoint(&Vec3{p[0], p[1], zm})
+		da, db := s*a.Z, s*b.Z
+		for j, d := range [2]float64{sliceNear, sliceFar} {
+			corners[j*4+i] = *invView.TransformPoint(a.Lerp(b, (d-da)/(db-da)))
+		}
+	}
+	return corners
+}
+
+type CascadeConfig struct {
+	Count int
+	// Lambda is the split blend passed to CascadeSplits.
+	Lambda float64
+	// Near and Far are the view depths covered by shadows, usually a
+	// shorter range than the camera's.
+	Near float64
+	Far  float64
+	// Resolution is the width and height of each shadow map in texels.
+	Resolution int
+	// CasterDistance extends each cascade towards the light so that casters
+	// outside the view still cast into it.
+	CasterDistance float64
+}
+
+type ShadowCascade struct {
+	Near           float64
+	Far            float64
+	Corners        [8]Vec3
+	Center         Vec3
+	Radius         float64
+	View           Mat4
+	Projection     Mat4
+	ViewProjection Mat4
+}
+
+/*
+ShadowCascades fits an orthographic shadow map for a directional light
+shining along lightDir to each slice of the camera frustum. Each cascade
+bounds its slice with a sphere and snaps to whole texels, so the shadow does
+not shimmer as the camera turns or moves.
+*/
+func (c ClipSpace) ShadowCascades(view, proj *Mat4, lightDir *Vec3, config CascadeConfig) []ShadowCascade {
+	splits := CascadeSplits(config.Near, config.Far, config.Count, config.Lambda)
+	dir := lightDir.Normalize()
+	up := &Vec3{0., 1., 0.}
+	if math.Abs(dir.Y) > 0.99 {
+		up = &Vec3{0., 0., 1.}
+	}
+	res := float64(config.Resolution)
+	cascades := make([]ShadowCascade, config.Count)
+	for i := range cascades {
+		cs := &cascades[i]
+		cs.Near, cs.Far = splits[i], splits[i+1]
+		cs.Corners = c.FrustumSliceCorners(view, proj, cs.Near, cs.Far)
+		center := &Vec3{}
+		for j := range cs.Corners {
+			center = center.Add(&cs.Corners[j])
+		}
+		center = center.DivideScalar(8.)
+		r := 0.
+		for j := range cs.Corners {
+			r = math.Max(r, center.Distance(&cs.Corners[j]))
+		}
+		// Rounding keeps the size, and so the texel size, fixed under
+		// rotation despite floating point noise.
+		r = math.Ceil(r*16.) / 16.
+		cs.Center, cs.Radius = *center, r
+
+		back := r + config.CasterDistance
+		lightView := c.LookAt(center.Subtract(dir.MultiplyScalar(back)), center, up)
+		lightProj := c.Ortho(-r, r, -r, r, 0., back+r)
+		origin := lightProj.Multiply(lightView).TransformPoint(&Vec3{})
+		half := 0.5 * res
+		lightProj.M14 += (math.Round(origin.X*half) - origin.X*half) / half
+		lightProj.M24 += (math.Round(origin.Y*half) - origin.Y*half) / half
+		cs.View, cs.Projection = *lightView, *lightProj
+		cs.ViewProjection = *lightProj.Multiply(lightView)
+	}
+	return cascades
+}
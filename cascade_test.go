@@ -0,0 +1,59 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestCascadeSplits(t *testing.T) {
+	u := mathg.CascadeSplits(1., 100., 4, 0.)
+	l := mathg.CascadeSplits(1., 100., 4, 1.)
+	if !slicesNearlyEqual(u, []float64{1., 25.75, 50.5, 75.25, 100.}) {
+		t.Fatalf("uniform splits = %v", u)
+	}
+	if !slicesNearlyEqual(l, []float64{1., math.Sqrt(10.), 10., math.Sqrt(1000.), 100.}) {
+		t.Fatalf("logarithmic splits = %v", l)
+	}
+}
+
+func TestFrustumSliceCorners(t *testing.T) {
+	clip := mathg.OpenGLClipSpace
+	view := clip.LookAt(&mathg.Vec3{1., 2., 3.}, &mathg.Vec3{0., 0., -4.}, &mathg.Vec3{0., 1., 0.})
+	proj := clip.Perspective(1., 1.5, 0.5, 40.)
+	got := clip.FrustumSliceCorners(view, proj, 0.5, 40.)
+	want := mathg.NewViewFrustum(proj.Multiply(view), clip).Corners()
+	for i := range want {
+		if !mathg.NearlyEqual(got[i].Distance(&want[i]), 0., 1e-6) {
+			t.Fatalf("corner %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestShadowCascades(t *testing.T) {
+	clip := mathg.OpenGLClipSpace
+	proj := clip.Perspective(1., 1.5, 0.1, 1000.)
+	config := mathg.CascadeConfig{Count: 3, Lambda: 0.7, Near: 0.1, Far: 60., Resolution: 1024, CasterDistance: 20.}
+	light := &mathg.Vec3{0.3, -1., 0.2}
+	eye := &mathg.Vec3{3., 2., 1.}
+	a := clip.ShadowCascades(clip.LookAt(eye, &mathg.Vec3{}, &mathg.Vec3{0., 1., 0.}), proj, light, config)
+	b := clip.ShadowCascades(clip.LookAt(eye, &mathg.Vec3{4., 1., -2.}, &mathg.Vec3{0., 1., 0.}), proj, light, config)
+	for i := range a {
+		for _, p := range a[i].Corners {
+			q := a[i].ViewProjection.TransformPoint(&p)
+			if math.Abs(q.X) > 1. || math.Abs(q.Y) > 1. || q.Z < -1. || q.Z > 1. {
+				t.Fatalf("cascade %d does not contain its slice: %v", i, q)
+			}
+		}
+		if a[i].Radius != b[i].Radius {
+			t.Fatalf("cascade %d changes size when the camera turns", i)
+		}
+		// Snapping keeps the world origin on a texel corner.
+		o := a[i].ViewProjection.TransformPoint(&mathg.Vec3{})
+		x := o.X * 512.
+		if !mathg.NearlyEqual(x, math.Round(x), 1e-6) {
+			t.Fatalf("cascade %d is not texel aligned: %f", i, x)
+		}
+	}
+}
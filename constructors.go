@@ -0,0 +1,157 @@
+package mathg
+
+import "math"
+
+/*
+Shear returns a matrix that adds factor times the source coordinate to the
+target coordinate, for example Shear(AxisX, AxisY, k) maps x to x + k*y.
+It panics when target and source are the same axis, which would be a scale.
+*/
+func (m *Mat4) Shear(target, source Axis, factor float64) *Mat4 {
+	checkShearAxes(target, source, AxisZ)
+	s := (&Mat4{}).Identity()
+	s.Set(int(target), int(source), factor)
+	return s
+}
+
+/*
+Reflection mirrors points across the plane a*x + b*y + c*z + d = 0, stored
+as {a, b, c, d}. The plane does not need to be normalized.
+*/
+func (m *Mat4) Reflection(plane *Vec4) *Mat4 {
+	l := math.Sqrt(plane.X*plane.X + plane.Y*plane.Y + plane.Z*plane.Z)
+	a, b, c, d := plane.X/l, plane.Y/l, plane.Z/l, plane.W/l
+	return &Mat4{
+		1. - 2.*a*a, -2. * b * a, -2. * c * a, 0.,
+		-2. * a * b, 1. - 2.*b*b, -2. * c * b, 0.,
+		-2. * a * c, -2. * b * c, 1. - 2.*c*c, 0.,
+		-2. * a * d, -2. * b * d, -2. * c * d, 1.,
+	}
+}
+
+/*
+PlanarShadow projects geometry onto the plane {a, b, c, d} away from light.
+A light with W = 1 is a point light at {X, Y, Z}; W = 0 is a directional
+light along {X, Y, Z}, where the sign does not matter. The result is
+projective, so transformed points need the divide done by TransformPoint.
+*/
+func (m *Mat4) PlanarShadow(light *Vec4, plane *Vec4) *Mat4 {
+	p := [4]float64{plane.X, plane.Y, plane.Z, plane.W}
+	l := [4]float64{light.X, light.Y, light.Z, light.W}
+	d := p[0]*l[0] + p[1]*l[1] + p[2]*l[2] + p[3]*l[3]
+	s := &Mat4{}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			v := -l[i] * p[j]
+			if i == j {
+				v += d
+			}
+			s.Set(i, j, v)
+		}
+	}
+	return s
+}
+
+func checkShearAxes(target, source, last Axis) {
+	if target == source || target < AxisX || target > last || source < AxisX || source > last {
+		panic("mathg: Shear needs two different axes of the space")
+	}
+}
+
+// Basis with local +Z along z and +Y as close to up as possible.
+func billboardBasis(position, z, up *Vec3) *Mat4 {
+	x := up.Cross(z)
+	if x.LengthSquared() < epsilon {
+		x = (&Vec3{0., 0., 1.}).Cross(z)
+		if x.LengthSquared() < epsilon {
+			x = (&Vec3{1., 0., 0.}).Cross(z)
+		}
+	}
+	x = x.Normalize()
+	y := z.Cross(x)
+	return &Mat4{
+		x.X, x.Y, x.Z, 0.,
+		y.X, y.Y, y.Z, 0.,
+		z.X, z.Y, z.Z, 0.,
+		position.X, position.Y, position.Z, 1.,
+	}
+}
+
+/*
+SphericalBillboard places an object at position with its local +Z facing
+camera and +Y as close to up as possible.
+*/
+func (m *Mat4) SphericalBillboard(position, camera, up *Vec3) *Mat4 {
+	z := camera.Subtract(position)
+	if z.LengthSquared() < epsilon {
+		z = &Vec3{0., 0., 1.}
+	}
+	return billboardBasis(position, z.Normalize(), up)
+}
+
+/*
+CylindricalBillboard places an object at position and turns it only about
+axis so that its local +Z faces camera as closely as possible. Local +Y is
+kept along axis.
+*/
+func (m *Mat4) CylindricalBillboard(position, camera, axis *Vec3) *Mat4 {
+	y := axis.Normalize()
+	to := camera.Subtract(position)
+	z := to.Subtract(y.MultiplyScalar(to.Dot(y)))
+	if z.LengthSquared() < epsilon {
+		// The camera is on the axis, any facing perpendicular to it will do.
+		z = y.Cross(&Vec3{1., 0., 0.})
+		if z.LengthSquared() < epsilon {
+			z = y.Cross(&Vec3{0., 0., 1.})
+		}
+	}
+	return billboardBasis(position, z.Normalize(), y)
+}
+
+/*
+Shear returns a 2D homogeneous matrix that adds factor times the source
+coordinate to the target coordinate. It panics unless target and source are
+AxisX and AxisY in either order.
+*/
+func (m *Mat3) Shear(target, source Axis, factor float64) *Mat3 {
+	checkShearAxes(target, source, AxisY)
+	s := (&Mat3{}).Identity()
+	s.Set(int(target), int(source), factor)
+	return s
+}
+
+/*
+Reflection mirrors 2D points across the line a*x + b*y + c = 0, stored as
+{a, b, c}. The line does not need to be normalized.
+*/
+func (m *Mat3) Reflection(line *Vec3) *Mat3 {
+	l := math.Hypot(line.X, line.Y)
+	a, b, c := line.X/l, line.Y/l, line.Z/l
+	return &Mat3{
+		1. - 2.*a*a, -2. * b * a, 0.,
+		-2. * a * b, 1. - 2.*b*b, 0.,
+		-2. * a * c, -2. * b * c, 1.,
+	}
+}
+
+/*
+PlanarShadow projects 2D geometry onto the line {a, b, c} away from light.
+A light with Z = 1 is a point light at {X, Y}; Z = 0 is a directional light
+shining along {X, Y}.
+*/
+func (m *Mat3) PlanarShadow(light *Vec3, line *Vec3) *Mat3 {
+	p := [3]float64{line.X, line.Y, line.Z}
+	l := [3]float64{light.X, light.Y, light.Z}
+	d := p[0]*l[0] + p[1]*l[1] + p[2]*l[2]
+	s := &Mat3{}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			v := -l[i] * p[j]
+			if i == j {
+				v += d
+			}
+			s.Set(i, j, v)
+		}
+	}
+	return s
+}
@@ -0,0 +1,74 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestMat4Reflection(t *testing.T) {
+	// Mirror across the plane y = 2.
+	m := (&mathg.Mat4{}).Reflection(&mathg.Vec4{0., 2., 0., -4.})
+	if p := m.TransformPoint(&mathg.Vec3{1., 5., -3.}); !vec3NearlyEqual(p, &mathg.Vec3{1., -1., -3.}) {
+		t.Fatalf("Reflection = %v", p)
+	}
+	if !mathg.NearlyEqual(m.Determinant(), -1., tolerance) {
+		t.Fatal("Reflection should have determinant -1")
+	}
+}
+
+func TestMat4PlanarShadow(t *testing.T) {
+	ground := &mathg.Vec4{0., 1., 0., 0.}
+	point := (&mathg.Mat4{}).PlanarShadow(&mathg.Vec4{0., 10., 0., 1.}, ground)
+	if p := point.TransformPoint(&mathg.Vec3{1., 5., 2.}); !vec3NearlyEqual(p, &mathg.Vec3{2., 0., 4.}) {
+		t.Fatalf("point light shadow = %v", p)
+	}
+	sun := (&mathg.Mat4{}).PlanarShadow(&mathg.Vec4{1., -1., 0., 0.}, ground)
+	if p := sun.TransformPoint(&mathg.Vec3{0., 3., 1.}); !vec3NearlyEqual(p, &mathg.Vec3{3., 0., 1.}) {
+		t.Fatalf("directional shadow = %v", p)
+	}
+}
+
+func TestMat4Billboard(t *testing.T) {
+	pos := &mathg.Vec3{1., 2., 3.}
+	camera := &mathg.Vec3{4., 6., 3.}
+	m := (&mathg.Mat4{}).SphericalBillboard(pos, camera, &mathg.Vec3{0., 1., 0.})
+	if z := m.TransformDirection(&mathg.Vec3{0., 0., 1.}); !vec3NearlyEqual(z, &mathg.Vec3{0.6, 0.8, 0.}) {
+		t.Fatalf("spherical facing = %v", z)
+	}
+	c := (&mathg.Mat4{}).CylindricalBillboard(pos, camera, &mathg.Vec3{0., 1., 0.})
+	if z := c.TransformDirection(&mathg.Vec3{0., 0., 1.}); !vec3NearlyEqual(z, &mathg.Vec3{1., 0., 0.}) {
+		t.Fatalf("cylindrical facing = %v", z)
+	}
+	if !mathg.NearlyEqual(c.Determinant(), 1., tolerance) || !vec3NearlyEqual(c.TransformPoint(&mathg.Vec3{}), pos) {
+		t.Fatal("billboard should be a rigid transform placed at position")
+	}
+}
+
+func TestMat3Shear(t *testing.T) {
+	m := (&mathg.Mat3{}).Shear(mathg.AxisX, mathg.AxisY, 2.)
+	if p := (&mathg.Vec3{1., 3., 1.}).MultiplyMat3(m); !vec3NearlyEqual(p, &mathg.Vec3{7., 3., 1.}) {
+		t.Fatalf("Shear = %v", p)
+	}
+	r := (&mathg.Mat3{}).Reflection(&mathg.Vec3{1., 0., -1.})
+	if p := (&mathg.Vec3{3., 2., 1.}).MultiplyMat3(r); !vec3NearlyEqual(p, &mathg.Vec3{-1., 2., 1.}) {
+		t.Fatalf("2D Reflection = %v", p)
+	}
+}
+
+func TestShearRejectsInvalidAxes(t *testing.T) {
+	for _, f := range []func(){
+		func() { (&mathg.Mat4{}).Shear(mathg.AxisY, mathg.AxisY, 1.) },
+		func() { (&mathg.Mat3{}).Shear(mathg.AxisX, mathg.AxisZ, 1.) },
+		func() { (&mathg.Mat3{}).Shear(mathg.AxisX, mathg.AxisX, 1.) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("Shear accepted invalid axes")
+				}
+			}()
+			f()
+		}()
+	}
+}
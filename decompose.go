@@ -0,0 +1,253 @@
+package mathg
+
+import (
+	"math"
+	"sort"
+)
+
+// Reorders eigenpairs so the values are descending.
+func sortEigen(values []float64, vectors [][]float64) ([]float64, [][]float64) {
+	n := len(values)
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return values[idx[i]] > values[idx[j]] })
+	sv := make([]float64, n)
+	svec := make([][]float64, n)
+	for r := range svec {
+		svec[r] = make([]float64, n)
+	}
+	for c, i := range idx {
+		sv[c] = values[i]
+		for r := 0; r < n; r++ {
+			svec[r][c] = vectors[r][i]
+		}
+	}
+	return sv, svec
+}
+
+func eigenSymmetric(a [][]float64) ([]float64, [][]float64) {
+	return sortEigen(jacobiEigenSymmetric(a))
+}
+
+/*
+svd returns u, s, v with a = u * diag(s) * v^T, s descending. It uses one
+sided Jacobi rotations on the columns of a, which keeps small singular values
+accurate instead of squaring the condition number through a^T a. Each column
+of u is orthogonalized against the larger ones, so u stays orthogonal when a
+is nearly singular; columns belonging to zero singular values are completed
+to an orthonormal basis.
+*/
+func svd(a [][]float64) (u [][]float64, s []float64, v [][]float64) {
+	n := len(a)
+	w := make([][]float64, n)
+	v = make([][]float64, n)
+	for i := range w {
+		w[i] = make([]float64, n)
+		copy(w[i], a[i])
+		v[i] = make([]float64, n)
+		v[i][i] = 1.
+	}
+	rotate := func(m [][]float64, p, q int, c, s float64) {
+		for k := range m {
+			mp, mq := m[k][p], m[k][q]
+			m[k][p] = c*mp - s*mq
+			m[k][q] = s*mp + c*mq
+		}
+	}
+	for sweep := 0; sweep < jacobiMaxSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				alpha, beta, gamma := 0., 0., 0.
+				for k := 0; k < n; k++ {
+					alpha += w[k][p] * w[k][p]
+					beta += w[k][q] * w[k][q]
+					gamma += w[k][p] * w[k][q]
+				}
+				if gamma == 0. || math.Abs(gamma) <= epsilon*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				zeta := (beta - alpha) / (2. * gamma)
+				t := 1. / (math.Abs(zeta) + math.Sqrt(1.+zeta*zeta))
+				if zeta < 0. {
+					t = -t
+				}
+				c := 1. / math.Sqrt(1.+t*t)
+				rotate(w, p, q, c, c*t)
+				rotate(v, p, q, c, c*t)
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+	norms := make([]float64, n)
+	for c := 0; c < n; c++ {
+		for r := 0; r < n; r++ {
+			norms[c] += w[r][c] * w[r][c]
+		}
+		norms[c] = math.Sqrt(norms[c])
+	}
+	s, v = sortEigen(norms, v)
+	_, w = sortEigen(norms, w)
+
+	u = make([][]float64, n)
+	for r := range u {
+		u[r] = make([]float64, n)
+	}
+	x := make([]float64, n)
+	for c := 0; c < n; c++ {
+		for r := 0; r < n; r++ {
+			x[r] = w[r][c]
+		}
+		// Two passes of Gram-Schmidt against the better determined columns.
+		for pass := 0; pass < 2; pass++ {
+			for j := 0; j < c; j++ {
+				d := 0.
+				for r := 0; r < n; r++ {
+					d += x[r] * u[r][j]
+				}
+				for r := 0; r < n; r++ {
+					x[r] -= d * u[r][j]
+				}
+			}
+		}
+		l := 0.
+		for r := 0; r < n; r++ {
+			l += x[r] * x[r]
+		}
+		l = math.Sqrt(l)
+		if l > epsilon*s[0] && l > 0. {
+			for r := 0; r < n; r++ {
+				u[r][c] = x[r] / l
+			}
+			continue
+		}
+		best := 0.
+		for e := 0; e < n; e++ {
+			b := make([]float64, n)
+			b[e] = 1.
+			for j := 0; j < c; j++ {
+				d := u[e][j]
+				for r := 0; r < n; r++ {
+					b[r] -= d * u[r][j]
+				}
+			}
+			l := 0.
+			for r := 0; r < n; r++ {
+				l += b[r] * b[r]
+			}
+			if l > best {
+				best = l
+				l = math.Sqrt(l)
+				for r := 0; r < n; r++ {
+					u[r][c] = b[r] / l
+				}
+			}
+		}
+	}
+	return u, s, v
+}
+
+func determinant(a [][]float64) float64 {
+	if len(a) == 2 {
+		return a[0][0]*a[1][1] - a[0][1]*a[1][0]
+	}
+	return mat3FromRows(a).Determinant()
+}
+
+/*
+polar returns the rotation r and symmetric s with a = r * s. When a contains
+a reflection it is moved into s by flipping its smallest singular direction.
+*/
+func polar(a [][]float64) (r [][]float64, s [][]float64) {
+	n := len(a)
+	u, _, v := svd(a)
+	if determinant(u)*determinant(v) < 0. {
+		for k := 0; k < n; k++ {
+			u[k][n-1] = -u[k][n-1]
+		}
+	}
+	r = make([][]float64, n)
+	for i := range r {
+		r[i] = make([]float64, n)
+		for j := 0; j < n; j++ {
+			for k := 0; k < n; k++ {
+				r[i][j] += u[i][k] * v[j][k]
+			}
+		}
+	}
+	s = make([][]float64, n)
+	for i := range s {
+		s[i] = make([]float64, n)
+		for j := 0; j < n; j++ {
+			for k := 0; k < n; k++ {
+				s[i][j] += r[k][i] * a[k][j]
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			m := 0.5 * (s[i][j] + s[j][i])
+			s[i][j], s[j][i] = m, m
+		}
+	}
+	return r, s
+}
+
+/*
+EigenSymmetric returns the eigenvalues of a symmetric matrix in descending
+order and the matching unit eigenvectors as the columns of vectors.
+*/
+func (m *Mat3) EigenSymmetric() (values *Vec3, vectors *Mat3) {
+	v, e := eigenSymmetric(m.rows())
+	return &Vec3{v[0], v[1], v[2]}, mat3FromRows(e)
+}
+
+/*
+SVD returns u, s and v with m = u * diag(s) * v^T. The singular values in s
+are descending and non-negative; u and v are orthogonal but may contain a
+reflection.
+*/
+func (m *Mat3) SVD() (u *Mat3, s *Vec3, v *Mat3) {
+	uu, ss, vv := svd(m.rows())
+	return mat3FromRows(uu), &Vec3{ss[0], ss[1], ss[2]}, mat3FromRows(vv)
+}
+
+/*
+PolarDecompose returns a proper rotation r and a symmetric s with m = r * s.
+*/
+func (m *Mat3) PolarDecompose() (r *Mat3, s *Mat3) {
+	rr, ss := polar(m.rows())
+	return mat3FromRows(rr), mat3FromRows(ss)
+}
+
+/*
+EigenSymmetric returns the eigenvalues of a symmetric matrix in descending
+order and the matching unit eigenvectors as the columns of vectors.
+*/
+func (m *Mat2) EigenSymmetric() (values *Vec2, vectors *Mat2) {
+	v, e := eigenSymmetric(m.rows())
+	return &Vec2{v[0], v[1]}, mat2FromRows(e)
+}
+
+/*
+SVD returns u, s and v with m = u * diag(s) * v^T. The singular values in s
+are descending and non-negative; u and v are orthogonal but may contain a
+reflection.
+*/
+func (m *Mat2) SVD() (u *Mat2, s *Vec2, v *Mat2) {
+	uu, ss, vv := svd(m.rows())
+	return mat2FromRows(uu), &Vec2{ss[0], ss[1]}, mat2FromRows(vv)
+}
+
+/*
+PolarDecompose returns a proper rotation r and a symmetric s with m = r * s.
+*/
+func (m *Mat2) PolarDecompose() (r *Mat2, s *Mat2) {
+	rr, ss := polar(m.rows())
+	return mat2FromRows(rr), mat2FromRows(ss)
+}
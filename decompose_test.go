@@ -0,0 +1,85 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestMat3EigenSymmetric(t *testing.T) {
+	m := &mathg.Mat3{
+		4., 1., 0.5,
+		1., 3., -1.,
+		0.5, -1., 2.,
+	}
+	values, vectors := m.EigenSymmetric()
+	if values.X < values.Y || values.Y < values.Z {
+		t.Fatalf("Eigenvalues not sorted: %v", *values)
+	}
+	diag := (&mathg.Mat3{}).Identity().Scale(values)
+	r := vectors.Multiply(diag).Multiply(vectors.Transpose())
+	if !mat3NearlyEqual(r, m) {
+		t.Fatalf("V D V^T = %v, want %v", *r, *m)
+	}
+}
+
+func TestMat3SVD(t *testing.T) {
+	m := &mathg.Mat3{
+		1., 2., 0.,
+		0., 1., 3.,
+		2., -1., 1.,
+	}
+	u, s, v := m.SVD()
+	r := u.Multiply((&mathg.Mat3{}).Identity().Scale(s)).Multiply(v.Transpose())
+	if !mat3NearlyEqual(r, m) {
+		t.Fatalf("U S V^T = %v, want %v", *r, *m)
+	}
+}
+
+func TestMat3PolarDecomposeReflection(t *testing.T) {
+	rot := (&mathg.Mat3{}).FromEuler(&mathg.Vec3{0.3, -0.4, 1.}, mathg.EulerXYZ)
+	m := rot.Multiply((&mathg.Mat3{}).Identity().Scale(&mathg.Vec3{2., -1., 0.5}))
+	r, s := m.PolarDecompose()
+	if !mathg.NearlyEqual(r.Determinant(), 1., tolerance) {
+		t.Fatalf("PolarDecompose rotation has determinant %f", r.Determinant())
+	}
+	if !mat3NearlyEqual(r.Multiply(s), m) || !mat3NearlyEqual(s, s.Transpose()) {
+		t.Fatal("PolarDecompose does not reproduce the matrix")
+	}
+}
+
+func TestMat2PolarDecompose(t *testing.T) {
+	m := &mathg.Mat2{2., 1., -1., 3.}
+	r, s := m.PolarDecompose()
+	p := r.Multiply(s)
+	if !mathg.NearlyEqual(r.Determinant(), 1., tolerance) ||
+		!mathg.NearlyEqual(p.M11, m.M11, tolerance) || !mathg.NearlyEqual(p.M21, m.M21, tolerance) ||
+		!mathg.NearlyEqual(p.M12, m.M12, tolerance) || !mathg.NearlyEqual(p.M22, m.M22, tolerance) {
+		t.Fatalf("Mat2 PolarDecompose = %v, %v", *r, *s)
+	}
+}
+
+func TestMat3PolarDecomposeIllConditioned(t *testing.T) {
+	// Singular values 1, 0.5 and 1e-9 behind two different rotations.
+	a := (&mathg.Mat3{}).FromEuler(&mathg.Vec3{0.3, -0.4, 1.}, mathg.EulerXYZ)
+	b := (&mathg.Mat3{}).FromEuler(&mathg.Vec3{-1.2, 0.7, 0.2}, mathg.EulerZYX)
+	m := a.Multiply((&mathg.Mat3{}).Identity().Scale(&mathg.Vec3{1., 0.5, 1e-9})).Multiply(b.Transpose())
+	u, s, v := m.SVD()
+	if !mat3NearlyEqual(u.Transpose().Multiply(u), (&mathg.Mat3{}).Identity()) ||
+		!mat3NearlyEqual(v.Transpose().Multiply(v), (&mathg.Mat3{}).Identity()) {
+		t.Fatal("SVD factors are not orthogonal")
+	}
+	if !mathg.NearlyEqual(s.Z, 1e-9, 1e-15) {
+		t.Fatalf("smallest singular value = %g", s.Z)
+	}
+	r, p := m.PolarDecompose()
+	if !mathg.NearlyEqual(r.Determinant(), 1., 1e-12) || !mat3NearlyEqual(r.Transpose().Multiply(r), (&mathg.Mat3{}).Identity()) {
+		t.Fatalf("PolarDecompose rotation has determinant %.15f", r.Determinant())
+	}
+	if !mat3NearlyEqual(r, a.Multiply(b.Transpose())) || !mat3NearlyEqual(r.Multiply(p), m) {
+		t.Fatal("PolarDecompose does not reproduce the matrix")
+	}
+	if o := m.Orthonormalize(); !mathg.NearlyEqual(o.Determinant(), 1., 1e-12) {
+		t.Fatalf("Orthonormalize determinant = %.15f", o.Determinant())
+	}
+}
@@ -0,0 +1,164 @@
+package mathg
+
+import "math"
+
+/*
+DualQuaternion represents a rigid transform as Real + eps * Dual, where Real
+is the rotation and Dual encodes the translation as 0.5 * t * Real.
+*/
+type DualQuaternion struct {
+	Real Quaternion
+	Dual Quaternion
+}
+
+func NewDualQuaternion(rotation *Quaternion, translation *Vec3) *DualQuaternion {
+	t := &Quaternion{translation.X, translation.Y, translation.Z, 0.}
+	return &DualQuaternion{*rotation, *t.Multiply(rotation).MultiplyScalar(0.5)}
+}
+
+func (d *DualQuaternion) Identity() *DualQuaternion {
+	return &DualQuaternion{Quaternion{0., 0., 0., 1.}, Quaternion{0., 0., 0., 0.}}
+}
+
+func (d *DualQuaternion) Clone() *DualQuaternion {
+	return &DualQuaternion{d.Real, d.Dual}
+}
+
+func (d *DualQuaternion) Add(d1 *DualQuaternion) *DualQuaternion {
+	return &DualQuaternion{
+		Quaternion{d.Real.X + d1.Real.X, d.Real.Y + d1.Real.Y, d.Real.Z + d1.Real.Z, d.Real.W + d1.Real.W},
+		Quaternion{d.Dual.X + d1.Dual.X, d.Dual.Y + d1.Dual.Y, d.Dual.Z + d1.Dual.Z, d.Dual.W + d1.Dual.W},
+	}
+}
+
+func (d *DualQuaternion) MultiplyScalar(scalar float64) *DualQuaternion {
+	return &DualQuaternion{*d.Real.MultiplyScalar(scalar), *d.Dual.MultiplyScalar(scalar)}
+}
+
+/*
+Multiply returns the transform that applies d1 first and then d.
+*/
+func (d *DualQuaternion) Multiply(d1 *DualQuaternion) *DualQuaternion {
+	r := d.Real.Multiply(&d1.Real)
+	a := d.Real.Multiply(&d1.Dual)
+	b := d.Dual.Multiply(&d1.Real)
+	return &DualQuaternion{*r, Quaternion{a.X + b.X, a.Y + b.Y, a.Z + b.Z, a.W + b.W}}
+}
+
+/*
+Conjugate applies the quaternion conjugate to both parts. For a unit dual
+quaternion this is the inverse transform.
+*/
+func (d *DualQuaternion) Conjugate() *DualQuaternion {
+	return &DualQuaternion{*d.Real.Conjugate(), *d.Dual.Conjugate()}
+}
+
+/*
+Normalize scales d to a unit real part and removes the component of the dual
+part that is not orthogonal to it.
+*/
+func (d *DualQuaternion) Normalize() *DualQuaternion {
+	l := d.Real.Magnitude()
+	r := d.Real.DivideScalar(l)
+	dual := d.Dual.DivideScalar(l)
+	dot := r.Dot(dual)
+	return &DualQuaternion{
+		*r,
+		Quaternion{dual.X - r.X*dot, dual.Y - r.Y*dot, dual.Z - r.Z*dot, dual.W - r.W*dot},
+	}
+}
+
+func (d *DualQuaternion) Rotation() *Quaternion {
+	q := d.Real
+	return &q
+}
+
+func (d *DualQuaternion) Translation() *Vec3 {
+	t := d.Dual.Multiply(d.Real.Conjugate())
+	return &Vec3{2. * t.X, 2. * t.Y, 2. * t.Z}
+}
+
+func (d *DualQuaternion) TransformPoint(v *Vec3) *Vec3 {
+	return d.TransformDirection(v).Add(d.Translation())
+}
+
+func (d *DualQuaternion) TransformDirection(v *Vec3) *Vec3 {
+	return d.Real.RotateVec3(v)
+}
+
+func (d *DualQuaternion) ToMat4() *Mat4 {
+	return d.Real.RotationMatrix4().Translation(d.Translation())
+}
+
+/*
+ToDualQuaternion expects a matrix made of rotation and translation only.
+*/
+func (m *Mat4) ToDualQuaternion() *DualQuaternion {
+	return NewDualQuaternion(m.ToQuaternion().Normalize(), &Vec3{m.M14, m.M24, m.M34})
+}
+
+/*
+Power raises a unit dual quaternion to exponent by scaling the angle and the
+displacement of its screw motion.
+*/
+func (d *DualQuaternion) Power(exponent float64) *DualQuaternion {
+	v := &Vec3{d.Real.X, d.Real.Y, d.Real.Z}
+	sinHalf := v.Magnitude()
+	if sinHalf < epsilon {
+		t := d.Translation().MultiplyScalar(exponent)
+		return NewDualQuaternion(&Quaternion{0., 0., 0., 1.}, t)
+	}
+	half := math.Atan2(sinHalf, d.Real.W)
+	l := v.DivideScalar(sinHalf)
+	pitch := -2. * d.Dual.W / sinHalf
+	dv := &Vec3{d.Dual.X, d.Dual.Y, d.Dual.Z}
+	moment := dv.Subtract(l.MultiplyScalar(pitch * 0.5 * d.Real.W)).DivideScalar(sinHalf)
+
+	half *= exponent
+	pitch *= exponent
+	s := math.Sin(half)
+	c := math.Cos(half)
+	r := l.MultiplyScalar(s)
+	dual := l.MultiplyScalar(pitch * 0.5 * c).Add(moment.MultiplyScalar(s))
+	return &DualQuaternion{
+		Quaternion{r.X, r.Y, r.Z, c},
+		Quaternion{dual.X, dual.Y, dual.Z, -pitch * 0.5 * s},
+	}
+}
+
+/*
+ScLerp interpolates along the screw motion between two unit dual
+quaternions, taking the shortest path.
+*/
+func (d *DualQuaternion) ScLerp(d1 *DualQuaternion, percent float64) *DualQuaternion {
+	if d.Real.Dot(&d1.Real) < 0. {
+		d1 = d1.MultiplyScalar(-1.)
+	}
+	diff := d.Conjugate().Multiply(d1)
+	return d.Multiply(diff.Power(percent)).Normalize()
+}
+
+/*
+BlendDualQuaternions performs dual quaternion linear blending as used for
+skinning. Each input is flipped onto the hemisphere of dqs[0] before the
+weighted sum is normalized. As for AverageQuaternions, nil weights blend
+equally and otherwise there must be one weight per input.
+*/
+func BlendDualQuaternions(dqs []*DualQuaternion, weights []float64) *DualQuaternion {
+	checkWeights("BlendDualQuaternions", len(dqs), weights)
+	sum := &DualQuaternion{}
+	if len(dqs) == 0 {
+		return sum.Identity()
+	}
+	for i, d := range dqs {
+		w := weightAt(weights, i)
+		if d.Real.Dot(&dqs[0].Real) < 0. {
+			w = -w
+		}
+		sum = sum.Add(d.MultiplyScalar(w))
+	}
+	if sum.Real.LengthSquared() < epsilon {
+		return sum.Identity()
+	}
+	return sum.Normalize()
+}
@@ -0,0 +1,72 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func vec3NearlyEqual(a, b *mathg.Vec3) bool {
+	return mathg.NearlyEqual(a.X, b.X, tolerance) && mathg.NearlyEqual(a.Y, b.Y, tolerance) &&
+		mathg.NearlyEqual(a.Z, b.Z, tolerance)
+}
+
+func TestDualQuaternionTransformPoint(t *testing.T) {
+	r := (&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0., 0., math.Pi / 2}, mathg.EulerXYZ)
+	d := mathg.NewDualQuaternion(r, &mathg.Vec3{1., 2., 3.})
+	p := d.TransformPoint(&mathg.Vec3{1., 0., 0.})
+	if !vec3NearlyEqual(p, &mathg.Vec3{1., 3., 3.}) {
+		t.Fatalf("TransformPoint = %v", *p)
+	}
+	back := d.Conjugate().TransformPoint(p)
+	if !vec3NearlyEqual(back, &mathg.Vec3{1., 0., 0.}) {
+		t.Fatalf("Conjugate did not invert the transform, got %v", *back)
+	}
+}
+
+func TestDualQuaternionMat4RoundTrip(t *testing.T) {
+	r := (&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.3, -0.7, 1.2}, mathg.EulerZYX)
+	d := mathg.NewDualQuaternion(r, &mathg.Vec3{-4., 0.5, 2.})
+	m := d.ToMat4()
+	e := m.ToDualQuaternion()
+	if !quaternionNearlyEqual(&e.Real, &d.Real) || !quaternionNearlyEqual(&e.Dual, &d.Dual) {
+		t.Fatalf("Mat4 round trip = %v, want %v", *e, *d)
+	}
+}
+
+func TestDualQuaternionScLerp(t *testing.T) {
+	r := (&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.4, 0.2, -0.9}, mathg.EulerXYZ)
+	d := mathg.NewDualQuaternion(r, &mathg.Vec3{3., -1., 2.})
+	half := (&mathg.DualQuaternion{}).Identity().ScLerp(d, 0.5)
+	full := half.Multiply(half)
+	if !quaternionNearlyEqual(&full.Real, &d.Real) || !quaternionNearlyEqual(&full.Dual, &d.Dual) {
+		t.Fatalf("ScLerp halfway applied twice = %v, want %v", *full, *d)
+	}
+	end := (&mathg.DualQuaternion{}).Identity().ScLerp(d, 1.)
+	if !vec3NearlyEqual(end.Translation(), d.Translation()) {
+		t.Fatalf("ScLerp end translation = %v", *end.Translation())
+	}
+}
+
+func TestBlendDualQuaternionsWeights(t *testing.T) {
+	r := (&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.2, 0.5, -0.3}, mathg.EulerXYZ)
+	dqs := []*mathg.DualQuaternion{
+		mathg.NewDualQuaternion(r, &mathg.Vec3{2., 0., 0.}),
+		mathg.NewDualQuaternion(r, &mathg.Vec3{0., 4., 0.}),
+	}
+	b := mathg.BlendDualQuaternions(dqs, nil)
+	if !quaternionNearlyEqual(b.Rotation(), r) || !vec3NearlyEqual(b.Translation(), &mathg.Vec3{1., 2., 0.}) {
+		t.Fatalf("BlendDualQuaternions with nil weights = %v", *b)
+	}
+	w := mathg.BlendDualQuaternions(dqs, []float64{0.5, 0.5})
+	if !quaternionNearlyEqual(&w.Real, &b.Real) || !quaternionNearlyEqual(&w.Dual, &b.Dual) {
+		t.Fatalf("nil weights = %v, equal weights = %v", *b, *w)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("BlendDualQuaternions accepted the wrong number of weights")
+		}
+	}()
+	mathg.BlendDualQuaternions(dqs, []float64{1.})
+}
@@ -0,0 +1,66 @@
+package mathg
+
+import "math"
+
+const jacobiMaxSweeps = 64
+
+/*
+jacobiEigenSymmetric diagonalizes the symmetric n x n matrix a with cyclic
+Jacobi rotations. It returns the eigenvalues and the matching eigenvectors
+stored as the columns of vectors. a is overwritten.
+*/
+func jacobiEigenSymmetric(a [][]float64) (values []float64, vectors [][]float64) {
+	n := len(a)
+	vectors = make([][]float64, n)
+	for i := range vectors {
+		vectors[i] = make([]float64, n)
+		vectors[i][i] = 1.
+	}
+	for sweep := 0; sweep < jacobiMaxSweeps; sweep++ {
+		off := 0.
+		scale := 0.
+		for i := 0; i < n; i++ {
+			scale += a[i][i] * a[i][i]
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off <= epsilon*epsilon*scale || off == 0. {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0. {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2. * a[p][q])
+				t := 1. / (math.Abs(theta) + math.Sqrt(theta*theta+1.))
+				if theta < 0. {
+					t = -t
+				}
+				c := 1. / math.Sqrt(t*t+1.)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := vectors[k][p], vectors[k][q]
+					vectors[k][p] = c*vkp - s*vkq
+					vectors[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	values = make([]float64, n)
+	for i := range values {
+		values[i] = a[i][i]
+	}
+	return values, vectors
+}
@@ -0,0 +1,194 @@
+package mathg
+
+import "math"
+
+/*
+EulerOrder names the axes of an Euler angle sequence in the order they are
+applied to a vector about the fixed world axes. EulerXYZ rotates about X
+first, then Y, then Z, which is the matrix Rz * Ry * Rx.
+
+Angles are always passed as a Vec3 holding the first, second and third
+rotation of the sequence in X, Y and Z respectively.
+*/
+type EulerOrder int
+
+const (
+	EulerXYZ EulerOrder = iota
+	EulerXZY
+	EulerYXZ
+	EulerYZX
+	EulerZXY
+	EulerZYX
+	EulerXYX
+	EulerXZX
+	EulerYXY
+	EulerYZY
+	EulerZXZ
+	EulerZYZ
+)
+
+/*
+Axis names a coordinate axis. It is used by EulerOrder.Axis and the Shear
+constructors.
+*/
+type Axis int
+
+const (
+	AxisX Axis = iota
+	AxisY
+	AxisZ
+)
+
+var eulerAxes = [...][3]Axis{
+	EulerXYZ: {AxisX, AxisY, AxisZ},
+	EulerXZY: {AxisX, AxisZ, AxisY},
+	EulerYXZ: {AxisY, AxisX, AxisZ},
+	EulerYZX: {AxisY, AxisZ, AxisX},
+	EulerZXY: {AxisZ, AxisX, AxisY},
+	EulerZYX: {AxisZ, AxisY, AxisX},
+	EulerXYX: {AxisX, AxisY, AxisX},
+	EulerXZX: {AxisX, AxisZ, AxisX},
+	EulerYXY: {AxisY, AxisX, AxisY},
+	EulerYZY: {AxisY, AxisZ, AxisY},
+	EulerZXZ: {AxisZ, AxisX, AxisZ},
+	EulerZYZ: {AxisZ, AxisY, AxisZ},
+}
+
+// Below this the middle angle is treated as gimbal locked.
+const eulerGimbalEpsilon float64 = 1e-9
+
+func (o EulerOrder) Axes() (first, second, third int) {
+	a := eulerAxes[o]
+	return int(a[0]), int(a[1]), int(a[2])
+}
+
+/*
+Axis returns the axis of rotation i of the sequence, from 0 to 2. Axes
+returns the same axes as ints.
+*/
+func (o EulerOrder) Axis(i int) Axis {
+	return eulerAxes[o][i]
+}
+
+func (o EulerOrder) IsProper() bool {
+	a := eulerAxes[o]
+	return a[0] == a[2]
+}
+
+// The i, j, k permutation and parity used by Shoemake's extraction.
+func (o EulerOrder) frame() (i, j, k int, odd bool) {
+	a := eulerAxes[o]
+	i, j = int(a[0]), int(a[1])
+	k = 3 - i - j
+	odd = (i+1)%3 != j
+	return
+}
+
+func axisRotationMat3(axis Axis, angle float64) *Mat3 {
+	m := &Mat3{}
+	m = m.Identity()
+	switch axis {
+	case AxisX:
+		return m.RotationX(angle)
+	case AxisY:
+		return m.RotationY(angle)
+	default:
+		return m.RotationZ(angle)
+	}
+}
+
+func axisRotationQuaternion(axis Axis, angle float64) *Quaternion {
+	half := angle * 0.5
+	q := &Quaternion{0., 0., 0., math.Cos(half)}
+	switch axis {
+	case AxisX:
+		q.X = math.Sin(half)
+	case AxisY:
+		q.Y = math.Sin(half)
+	default:
+		q.Z = math.Sin(half)
+	}
+	return q
+}
+
+func (m *Mat3) FromEuler(angles *Vec3, order EulerOrder) *Mat3 {
+	a := eulerAxes[order]
+	r := axisRotationMat3(a[1], angles.Y).Multiply(axisRotationMat3(a[0], angles.X))
+	return axisRotationMat3(a[2], angles.Z).Multiply(r)
+}
+
+/*
+ToEuler expects a pure rotation matrix. When the sequence is gimbal locked
+the third angle is set to zero and the first angle absorbs the rotation.
+*/
+func (m *Mat3) ToEuler(order EulerOrder) *Vec3 {
+	i, j, k, odd := order.frame()
+	e := &Vec3{}
+	gimbal := false
+	if order.IsProper() {
+		sy := math.Sqrt(m.At(i, j)*m.At(i, j) + m.At(i, k)*m.At(i, k))
+		gimbal = sy <= eulerGimbalEpsilon
+		if !gimbal {
+			e.X = math.Atan2(m.At(i, j), m.At(i, k))
+			e.Y = math.Atan2(sy, m.At(i, i))
+			e.Z = math.Atan2(m.At(j, i), -m.At(k, i))
+		} else {
+			e.X = math.Atan2(-m.At(j, k), m.At(j, j))
+			e.Y = math.Atan2(sy, m.At(i, i))
+			e.Z = 0.
+		}
+	} else {
+		cy := math.Sqrt(m.At(i, i)*m.At(i, i) + m.At(j, i)*m.At(j, i))
+		gimbal = cy <= eulerGimbalEpsilon
+		if !gimbal {
+			e.X = math.Atan2(m.At(k, j), m.At(k, k))
+			e.Y = math.Atan2(-m.At(k, i), cy)
+			e.Z = math.Atan2(m.At(j, i), m.At(i, i))
+		} else {
+			e.X = math.Atan2(-m.At(j, k), m.At(j, j))
+			e.Y = math.Atan2(-m.At(k, i), cy)
+			e.Z = 0.
+		}
+	}
+	if odd {
+		e = e.Negative()
+		// Keep the middle angle of proper sequences in [0, pi].
+		if order.IsProper() && e.Y < 0. {
+			e.Y = -e.Y
+			if !gimbal {
+				e.X = wrapAngle(e.X + math.Pi)
+				e.Z = wrapAngle(e.Z + math.Pi)
+			}
+		}
+	}
+	return e
+}
+
+func wrapAngle(angle float64) float64 {
+	angle = math.Mod(angle+math.Pi, 2.*math.Pi)
+	if angle <= 0. {
+		angle += 2. * math.Pi
+	}
+	return angle - math.Pi
+}
+
+func (m *Mat4) FromEuler(angles *Vec3, order EulerOrder) *Mat4 {
+	return (&Mat3{}).FromEuler(angles, order).ToMat4()
+}
+
+/*
+ToEuler reads the upper 3x3 of the matrix, which must be free of scale.
+*/
+func (m *Mat4) ToEuler(order EulerOrder) *Vec3 {
+	return m.Mat3().ToEuler(order)
+}
+
+func (q *Quaternion) FromEuler(angles *Vec3, order EulerOrder) *Quaternion {
+	a := eulerAxes[order]
+	r := axisRotationQuaternion(a[1], angles.Y).Multiply(axisRotationQuaternion(a[0], angles.X))
+	return axisRotationQuaternion(a[2], angles.Z).Multiply(r)
+}
+
+func (q *Quaternion) ToEuler(order EulerOrder) *Vec3 {
+	return q.Normalize().RotationMatrix().ToEuler(order)
+}
@@ -0,0 +1,79 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+const tolerance float64 = 1e-9
+
+var eulerOrders = []mathg.EulerOrder{
+	mathg.EulerXYZ, mathg.EulerXZY, mathg.EulerYXZ, mathg.EulerYZX,
+	mathg.EulerZXY, mathg.EulerZYX, mathg.EulerXYX, mathg.EulerXZX,
+	mathg.EulerYXY, mathg.EulerYZY, mathg.EulerZXZ, mathg.EulerZYZ,
+}
+
+func mat3NearlyEqual(a, b *mathg.Mat3) bool {
+	return mathg.NearlyEqual(a.M11, b.M11, tolerance) && mathg.NearlyEqual(a.M21, b.M21, tolerance) &&
+		mathg.NearlyEqual(a.M31, b.M31, tolerance) && mathg.NearlyEqual(a.M12, b.M12, tolerance) &&
+		mathg.NearlyEqual(a.M22, b.M22, tolerance) && mathg.NearlyEqual(a.M32, b.M32, tolerance) &&
+		mathg.NearlyEqual(a.M13, b.M13, tolerance) && mathg.NearlyEqual(a.M23, b.M23, tolerance) &&
+		mathg.NearlyEqual(a.M33, b.M33, tolerance)
+}
+
+func TestEulerMat3RoundTrip(t *testing.T) {
+	angles := []*mathg.Vec3{
+		{0.1, 0.2, 0.3},
+		{-1.2, 0.7, 2.5},
+		{0.4, math.Pi / 2, -0.3},
+		{0.4, 0., -0.3},
+	}
+	for _, order := range eulerOrders {
+		for _, a := range angles {
+			m := (&mathg.Mat3{}).FromEuler(a, order)
+			e := m.ToEuler(order)
+			r := (&mathg.Mat3{}).FromEuler(e, order)
+			if !mat3NearlyEqual(m, r) {
+				t.Fatalf("Euler order %d round trip of %v failed, got %v", order, *a, *e)
+			}
+		}
+	}
+}
+
+func TestEulerQuaternionMatchesMat3(t *testing.T) {
+	a := &mathg.Vec3{0.3, 0.6, -1.1}
+	for _, order := range eulerOrders {
+		q := (&mathg.Quaternion{}).FromEuler(a, order)
+		m := (&mathg.Mat3{}).FromEuler(a, order)
+		if !mat3NearlyEqual(q.RotationMatrix(), m) {
+			t.Fatalf("Euler order %d quaternion does not match matrix", order)
+		}
+		e := q.ToEuler(order)
+		if !mathg.NearlyEqual(e.X, a.X, tolerance) || !mathg.NearlyEqual(e.Y, a.Y, tolerance) || !mathg.NearlyEqual(e.Z, a.Z, tolerance) {
+			t.Fatalf("Euler order %d quaternion ToEuler = %v", order, *e)
+		}
+	}
+}
+
+func TestEulerGimbalLock(t *testing.T) {
+	m := (&mathg.Mat3{}).FromEuler(&mathg.Vec3{0.5, math.Pi / 2, 0.25}, mathg.EulerXYZ)
+	e := m.ToEuler(mathg.EulerXYZ)
+	if e.Z != 0. {
+		t.Fatalf("Gimbal locked ToEuler should zero the third angle, got %v", *e)
+	}
+	if !mat3NearlyEqual((&mathg.Mat3{}).FromEuler(e, mathg.EulerXYZ), m) {
+		t.Fatal("Gimbal locked ToEuler does not reproduce the rotation")
+	}
+}
+
+func TestEulerOrderAxis(t *testing.T) {
+	a0, a1, a2 := mathg.EulerZXY.Axes()
+	if a0 != 2 || a1 != 0 || a2 != 1 {
+		t.Fatalf("EulerZXY.Axes = %d, %d, %d", a0, a1, a2)
+	}
+	if mathg.EulerZXY.Axis(0) != mathg.AxisZ || mathg.EulerZXY.Axis(1) != mathg.AxisX || mathg.EulerZXY.Axis(2) != mathg.AxisY {
+		t.Fatal("EulerZXY.Axis disagrees with Axes")
+	}
+}
@@ -0,0 +1,148 @@
+package mathg
+
+/*
+ViewFrustum is the region a view-projection matrix renders, as six planes
+whose normals point inwards. The planes are ordered left, right, bottom,
+top, near, far; see FrustumLeft and the following constants.
+*/
+type ViewFrustum struct {
+	Planes [6]Plane
+}
+
+const (
+	FrustumLeft = iota
+	FrustumRight
+	FrustumBottom
+	FrustumTop
+	FrustumNear
+	FrustumFar
+)
+
+/*
+NewViewFrustum extracts the planes of m (Gribb and Hartmann), which may be a
+projection alone or a view-projection, in which case the planes are in world
+space. clip gives the depth range and whether depth is reversed. With an
+infinite far plane the far plane accepts everything.
+*/
+func NewViewFrustum(m *Mat4, clip ClipSpace) *ViewFrustum {
+	r0, r1, r2, r3 := m.Row(0), m.Row(1), m.Row(2), m.Row(3)
+	// Clip space bounds are -w <= x, y <= w and zn <= z <= zf with zn, zf
+	// either -w or 0 and w.
+	low := r2
+	if clip.Depth == DepthNegativeOneToOne {
+		low = r3.Add(r2)
+	}
+	high := r3.Subtract(r2)
+	near, far := low, high
+	if clip.ReversedZ {
+		near, far = high, low
+	}
+	planes := [6]*Vec4{r3.Add(r0), r3.Subtract(r0), r3.Add(r1), r3.Subtract(r1), near, far}
+	f := &ViewFrustum{}
+	for i, v := range planes {
+		p := (&Plane{}).FromVec4(v).Normalize()
+		if p.Normal.IsZero() {
+			p.D = 1.
+		}
+		f.Planes[i] = *p
+	}
+	return f
+}
+
+// Point shared by three planes.
+func planesIntersection(a, b, c *Plane) *Vec3 {
+	bc := b.Normal.Cross(&c.Normal)
+	ca := c.Normal.Cross(&a.Normal)
+	ab := a.Normal.Cross(&b.Normal)
+	d := a.Normal.Dot(bc)
+	return bc.MultiplyScalar(-a.D).Add(ca.MultiplyScalar(-b.D)).Add(ab.MultiplyScalar(-c.D)).DivideScalar(d)
+}
+
+/*
+Corners returns the near corners followed by the far corners, each in the
+order left bottom, right bottom, left top, right top. The far corners are
+not finite for an infinite far plane.
+*/
+func (f *ViewFrustum) Corners() [8]Vec3 {
+	var c [8]Vec3
+	p := &f.Planes
+	for i, depth := range [2]int{FrustumNear, FrustumFar} {
+		c[i*4+0] = *planesIntersection(&p[FrustumLeft], &p[FrustumBottom], &p[depth])
+		c[i*4+1] = *planesIntersection(&p[FrustumRight], &p[FrustumBottom], &p[depth])
+		c[i*4+2] = *planesIntersection(&p[FrustumLeft], &p[FrustumTop], &p[depth])
+		c[i*4+3] = *planesIntersection(&p[FrustumRight], &p[FrustumTop], &p[depth])
+	}
+	return c
+}
+
+func (f *ViewFrustum) ContainsPoint(point *Vec3) bool {
+	for i := range f.Planes {
+		if f.Planes[i].SignedDistance(point) < 0. {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *ViewFrustum) IntersectsSphere(s *Sphere) Containment {
+	result := Inside
+	for i := range f.Planes {
+		d := f.Planes[i].SignedDistance(&s.Center)
+		if d < -s.Radius {
+			return Outside
+		}
+		if d < s.Radius {
+			result = Intersecting
+		}
+	}
+	return result
+}
+
+/*
+IntersectsAABB is conservative: a box near a frustum edge may be reported as
+Intersecting while lying just outside.
+*/
+func (f *ViewFrustum) IntersectsAABB(b *AABB) Containment {
+	c := b.Center()
+	e := b.Extents()
+	result := Inside
+	for i := range f.Planes {
+		p := &f.Planes[i]
+		d := p.SignedDistance(c)
+		r := boxRadius(e, &p.Normal)
+		if d < -r {
+			return Outside
+		}
+		if d < r {
+			result = Intersecting
+		}
+	}
+	return result
+}
+
+/*
+CullSpheres tests every sphere and stores the results in result, which is
+grown as needed and returned so it can be reused across frames.
+*/
+func (f *ViewFrustum) CullSpheres(spheres []Sphere, result []Containment) []Containment {
+	result = resizeContainment(result, len(spheres))
+	for i := range spheres {
+		result[i] = f.IntersectsSphere(&spheres[i])
+	}
+	return result
+}
+
+func (f *ViewFrustum) CullAABBs(boxes []AABB, result []Containment) []Containment {
+	result = resizeContainment(result, len(boxes))
+	for i := range boxes {
+		result[i] = f.IntersectsAABB(&boxes[i])
+	}
+	return result
+}
+
+func resizeContainment(c []Containment, n int) []Containment {
+	if cap(c) < n {
+		return make([]Containment, n)
+	}
+	return c[:n]
+}
@@ -0,0 +1,71 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestViewFrustumCorners(t *testing.T) {
+	clips := []mathg.ClipSpace{
+		mathg.OpenGLClipSpace,
+		mathg.Direct3DClipSpace,
+		{Depth: mathg.DepthZeroToOne, ReversedZ: true},
+	}
+	for _, clip := range clips {
+		// A 90 degree square frustum looking down the view axis from the origin.
+		m := clip.Perspective(math.Pi/2., 1., 1., 10.)
+		f := mathg.NewViewFrustum(m, clip)
+		s := -1.
+		if clip.Handedness == mathg.LeftHanded {
+			s = 1.
+		}
+		c := f.Corners()
+		if !vec3NearlyEqual(&c[0], &mathg.Vec3{-1., -1., s}) || !vec3NearlyEqual(&c[7], &mathg.Vec3{10., 10., 10. * s}) {
+			t.Fatalf("%+v corners = %v", clip, c)
+		}
+		if !f.ContainsPoint(&mathg.Vec3{0.5, -0.5, 2. * s}) || f.ContainsPoint(&mathg.Vec3{0., 0., 11. * s}) || f.ContainsPoint(&mathg.Vec3{0., 0., 0.5 * s}) {
+			t.Fatalf("%+v ContainsPoint", clip)
+		}
+	}
+}
+
+func TestViewFrustumCulling(t *testing.T) {
+	clip := mathg.OpenGLClipSpace
+	view := clip.LookAt(&mathg.Vec3{0., 0., 5.}, &mathg.Vec3{}, &mathg.Vec3{0., 1., 0.})
+	f := mathg.NewViewFrustum(clip.Perspective(math.Pi/2., 1., 1., 10.).Multiply(view), clip)
+
+	spheres := []mathg.Sphere{
+		{Center: mathg.Vec3{0., 0., 0.}, Radius: 1.},
+		{Center: mathg.Vec3{0., 0., -5.}, Radius: 1.},
+		{Center: mathg.Vec3{20., 0., 0.}, Radius: 1.},
+	}
+	got := f.CullSpheres(spheres, nil)
+	want := []mathg.Containment{mathg.Inside, mathg.Intersecting, mathg.Outside}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("CullSpheres = %v, want %v", got, want)
+		}
+	}
+
+	boxes := []mathg.AABB{
+		{Min: mathg.Vec3{-1., -1., -1.}, Max: mathg.Vec3{1., 1., 1.}},
+		{Min: mathg.Vec3{-1., -1., 3.}, Max: mathg.Vec3{1., 1., 6.}},
+		{Min: mathg.Vec3{-1., -1., 5.5}, Max: mathg.Vec3{1., 1., 7.}},
+	}
+	got = f.CullAABBs(boxes, got)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("CullAABBs = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestViewFrustumInfiniteFar(t *testing.T) {
+	clip := mathg.ClipSpace{Depth: mathg.DepthZeroToOne, ReversedZ: true, InfiniteFar: true}
+	f := mathg.NewViewFrustum(clip.Perspective(math.Pi/2., 1., 1., 0.), clip)
+	if !f.ContainsPoint(&mathg.Vec3{0., 0., -1e12}) || f.ContainsPoint(&mathg.Vec3{0., 0., -0.5}) {
+		t.Fatal("infinite frustum should only be bounded by the near plane in depth")
+	}
+}
@@ -0,0 +1,581 @@
+package mathg
+
+import "math"
+
+/*
+Plane is the set of points p with Normal.Dot(p) + D = 0. Points on the side
+the normal points to have a positive signed distance.
+*/
+type Plane struct {
+	Normal Vec3
+	D      float64
+}
+
+/*
+FromVec4 reads a plane stored as {a, b, c, d}, the form used by
+Mat4.TransformPlane and Mat4.Reflection.
+*/
+func (p *Plane) FromVec4(v *Vec4) *Plane {
+	return &Plane{Vec3{v.X, v.Y, v.Z}, v.W}
+}
+
+func (p *Plane) Vec4() *Vec4 {
+	return &Vec4{p.Normal.X, p.Normal.Y, p.Normal.Z, p.D}
+}
+
+func (p *Plane) FromPointNormal(point, normal *Vec3) *Plane {
+	n := normal.Normalize()
+	return &Plane{*n, -n.Dot(point)}
+}
+
+/*
+FromPoints builds the plane through a, b and c, facing the side from which
+they appear counter clockwise.
+*/
+func (p *Plane) FromPoints(a, b, c *Vec3) *Plane {
+	return p.FromPointNormal(a, b.Subtract(a).Cross(c.Subtract(a)))
+}
+
+/*
+Normalize scales the plane to a unit normal, so SignedDistance returns true
+distances. A plane with a zero normal is returned unchanged.
+*/
+func (p *Plane) Normalize() *Plane {
+	l := p.Normal.Magnitude()
+	if l == 0. {
+		return &Plane{p.Normal, p.D}
+	}
+	return &Plane{*p.Normal.DivideScalar(l), p.D / l}
+}
+
+func (p *Plane) SignedDistance(point *Vec3) float64 {
+	return p.Normal.Dot(point) + p.D
+}
+
+func (p *Plane) ClosestPoint(point *Vec3) *Vec3 {
+	return point.Subtract(p.Normal.MultiplyScalar(p.SignedDistance(point) / p.Normal.LengthSquared()))
+}
+
+type Sphere struct {
+	Center Vec3
+	Radius float64
+}
+
+/*
+AABB is an axis aligned bounding box. A box with Min greater than Max on any
+axis is empty.
+*/
+type AABB struct {
+	Min Vec3
+	Max Vec3
+}
+
+func (b *AABB) Center() *Vec3 {
+	return b.Min.Add(&b.Max).MultiplyScalar(0.5)
+}
+
+func (b *AABB) Extents() *Vec3 {
+	return b.Max.Subtract(&b.Min).MultiplyScalar(0.5)
+}
+
+/*
+Containment is the result of a bounding volume test against a region.
+*/
+type Containment int
+
+const (
+	Outside Containment = iota
+	Intersecting
+	Inside
+)
+
+// Projected radius of a box with the given half extents onto n.
+func boxRadius(extents, n *Vec3) float64 {
+	return extents.X*math.Abs(n.X) + extents.Y*math.Abs(n.Y) + extents.Z*math.Abs(n.Z)
+}
+
+/*
+Transform moves the plane by m, which must be invertible.
+*/
+func (p *Plane) Transform(m *Mat4) *Plane {
+	return (&Plane{}).FromVec4(m.TransformPlane(p.Vec4()))
+}
+
+type Ray struct {
+	Origin    Vec3
+	Direction Vec3
+}
+
+func (r *Ray) At(t float64) *Vec3 {
+	return r.Origin.Add(r.Direction.MultiplyScalar(t))
+}
+
+/*
+ClosestPoint returns the point of the ray nearest to p, clamped to the
+origin for points behind it.
+*/
+func (r *Ray) ClosestPoint(p *Vec3) *Vec3 {
+	t := p.Subtract(&r.Origin).Dot(&r.Direction) / r.Direction.LengthSquared()
+	return r.At(math.Max(t, 0.))
+}
+
+/*
+Transform moves the ray by m. The direction is transformed but not
+normalized, so ray parameters stay consistent with the original.
+*/
+func (r *Ray) Transform(m *Mat4) *Ray {
+	return &Ray{*m.TransformPoint(&r.Origin), *m.TransformDirection(&r.Direction)}
+}
+
+func (r *Ray) TransformBy(t *Transform) *Ray {
+	return &Ray{*t.TransformPoint(&r.Origin), *t.TransformDirection(&r.Direction)}
+}
+
+/*
+Line is the infinite line through Point along Direction.
+*/
+type Line struct {
+	Point     Vec3
+	Direction Vec3
+}
+
+func (l *Line) FromPoints(a, b *Vec3) *Line {
+	return &Line{*a, *b.Subtract(a)}
+}
+
+func (l *Line) ClosestPoint(p *Vec3) *Vec3 {
+	t := p.Subtract(&l.Point).Dot(&l.Direction) / l.Direction.LengthSquared()
+	return l.Point.Add(l.Direction.MultiplyScalar(t))
+}
+
+func (l *Line) Distance(p *Vec3) float64 {
+	return l.ClosestPoint(p).Distance(p)
+}
+
+func (l *Line) Transform(m *Mat4) *Line {
+	return &Line{*m.TransformPoint(&l.Point), *m.TransformDirection(&l.Direction)}
+}
+
+type Segment struct {
+	A Vec3
+	B Vec3
+}
+
+func (s *Segment) Length() float64 {
+	return s.A.Distance(&s.B)
+}
+
+func (s *Segment) ClosestPoint(p *Vec3) *Vec3 {
+	d := s.B.Subtract(&s.A)
+	l := d.LengthSquared()
+	if l == 0. {
+		return &Vec3{s.A.X, s.A.Y, s.A.Z}
+	}
+	t := Clamp(p.Subtract(&s.A).Dot(d)/l, 0., 1.)
+	return s.A.Add(d.MultiplyScalar(t))
+}
+
+func (s *Segment) Distance(p *Vec3) float64 {
+	return s.ClosestPoint(p).Distance(p)
+}
+
+func (s *Segment) Transform(m *Mat4) *Segment {
+	return &Segment{*m.TransformPoint(&s.A), *m.TransformPoint(&s.B)}
+}
+
+// Largest factor by which the upper 3x3 of m can stretch a length, its
+// largest singular value. Column lengths fall short of it under shear.
+func maxScale(m *Mat4) float64 {
+	_, s, _ := svd(m.Mat3().rows())
+	return s[0]
+}
+
+/*
+FromPoints returns a bounding sphere of points using Ritter's algorithm,
+which is within a few percent of the smallest one.
+*/
+func (s *Sphere) FromPoints(points []Vec3) *Sphere {
+	if len(points) == 0 {
+		return &Sphere{}
+	}
+	farthest := func(from *Vec3) *Vec3 {
+		f, d := &points[0], -1.
+		for i := range points {
+			if di := from.Distance(&points[i]); di > d {
+				f, d = &points[i], di
+			}
+		}
+		return f
+	}
+	a := farthest(&points[0])
+	b := farthest(a)
+	r := &Sphere{*a.Lerp(b, 0.5), 0.5 * a.Distance(b)}
+	for i := range points {
+		r = r.ExpandToPoint(&points[i])
+	}
+	return r
+}
+
+func (s *Sphere) Volume() float64 {
+	return 4. / 3. * math.Pi * s.Radius * s.Radius * s.Radius
+}
+
+func (s *Sphere) SurfaceArea() float64 {
+	return 4. * math.Pi * s.Radius * s.Radius
+}
+
+func (s *Sphere) ContainsPoint(p *Vec3) bool {
+	return s.Center.Distance(p) <= s.Radius
+}
+
+func (s *Sphere) ContainsSphere(s1 *Sphere) Containment {
+	d := s.Center.Distance(&s1.Center)
+	if d > s.Radius+s1.Radius {
+		return Outside
+	}
+	if d+s1.Radius <= s.Radius {
+		return Inside
+	}
+	return Intersecting
+}
+
+/*
+ExpandToPoint returns the smallest sphere holding s and p that keeps the
+far side of s fixed.
+*/
+func (s *Sphere) ExpandToPoint(p *Vec3) *Sphere {
+	d := s.Center.Distance(p)
+	if d <= s.Radius {
+		return &Sphere{s.Center, s.Radius}
+	}
+	r := 0.5 * (s.Radius + d)
+	return &Sphere{*s.Center.Lerp(p, (r-s.Radius)/d), r}
+}
+
+func (s *Sphere) Merge(s1 *Sphere) *Sphere {
+	d := s.Center.Distance(&s1.Center)
+	if d+s1.Radius <= s.Radius {
+		return &Sphere{s.Center, s.Radius}
+	}
+	if d+s.Radius <= s1.Radius {
+		return &Sphere{s1.Center, s1.Radius}
+	}
+	r := 0.5 * (d + s.Radius + s1.Radius)
+	return &Sphere{*s.Center.Lerp(&s1.Center, (r-s.Radius)/d), r}
+}
+
+/*
+Transform moves the sphere by m. Under non uniform scale the result bounds
+the ellipsoid the sphere becomes.
+*/
+func (s *Sphere) Transform(m *Mat4) *Sphere {
+	return &Sphere{*m.TransformPoint(&s.Center), s.Radius * maxScale(m)}
+}
+
+func (s *Sphere) TransformBy(t *Transform) *Sphere {
+	return s.Transform(t.ToMat4())
+}
+
+func (s *Sphere) AABB() *AABB {
+	r := &Vec3{s.Radius, s.Radius, s.Radius}
+	return &AABB{*s.Center.Subtract(r), *s.Center.Add(r)}
+}
+
+/*
+EmptyAABB returns a box containing nothing, the identity for Merge and
+ExpandToPoint.
+*/
+func EmptyAABB() *AABB {
+	inf := math.Inf(1)
+	return &AABB{Vec3{inf, inf, inf}, Vec3{-inf, -inf, -inf}}
+}
+
+func (b *AABB) FromPoints(points []Vec3) *AABB {
+	r := EmptyAABB()
+	for i := range points {
+		r = r.ExpandToPoint(&points[i])
+	}
+	return r
+}
+
+func (b *AABB) IsEmpty() bool {
+	return b.Min.X > b.Max.X || b.Min.Y > b.Max.Y || b.Min.Z > b.Max.Z
+}
+
+func (b *AABB) Size() *Vec3 {
+	return b.Max.Subtract(&b.Min)
+}
+
+func (b *AABB) Volume() float64 {
+	if b.IsEmpty() {
+		return 0.
+	}
+	s := b.Size()
+	return s.X * s.Y * s.Z
+}
+
+func (b *AABB) SurfaceArea() float64 {
+	if b.IsEmpty() {
+		return 0.
+	}
+	s := b.Size()
+	return 2. * (s.X*s.Y + s.Y*s.Z + s.Z*s.X)
+}
+
+func (b *AABB) ContainsPoint(p *Vec3) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X &&
+		p.Y >= b.Min.Y && p.Y <= b.Max.Y &&
+		p.Z >= b.Min.Z && p.Z <= b.Max.Z
+}
+
+func (b *AABB) ContainsAABB(b1 *AABB) Containment {
+	if b1.Max.X < b.Min.X || b1.Min.X > b.Max.X ||
+		b1.Max.Y < b.Min.Y || b1.Min.Y > b.Max.Y ||
+		b1.Max.Z < b.Min.Z || b1.Min.Z > b.Max.Z {
+		return Outside
+	}
+	if b.ContainsPoint(&b1.Min) && b.ContainsPoint(&b1.Max) {
+		return Inside
+	}
+	return Intersecting
+}
+
+func (b *AABB) ClosestPoint(p *Vec3) *Vec3 {
+	return p.Clamp(&b.Min, &b.Max)
+}
+
+func (b *AABB) Merge(b1 *AABB) *AABB {
+	return &AABB{*b.Min.Min(&b1.Min), *b.Max.Max(&b1.Max)}
+}
+
+func (b *AABB) ExpandToPoint(p *Vec3) *AABB {
+	return &AABB{*b.Min.Min(p), *b.Max.Max(p)}
+}
+
+/*
+Expand grows the box by margin on every side. A negative margin shrinks it.
+*/
+func (b *AABB) Expand(margin float64) *AABB {
+	return &AABB{*b.Min.SubtractScalar(margin), *b.Max.AddScalar(margin)}
+}
+
+/*
+Transform returns the box bounding b moved by the affine matrix m, using
+Arvo's method, which is exact for the corners of b and much cheaper than
+transforming all eight.
+*/
+func (b *AABB) Transform(m *Mat4) *AABB {
+	if b.IsEmpty() {
+		return EmptyAABB()
+	}
+	min := [3]float64{m.M14, m.M24, m.M34}
+	max := min
+	lo := [3]float64{b.Min.X, b.Min.Y, b.Min.Z}
+	hi := [3]float64{b.Max.X, b.Max.Y, b.Max.Z}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			e := m.At(i, j) * lo[j]
+			f := m.At(i, j) * hi[j]
+			min[i] += math.Min(e, f)
+			max[i] += math.Max(e, f)
+		}
+	}
+	return &AABB{Vec3{min[0], min[1], min[2]}, Vec3{max[0], max[1], max[2]}}
+}
+
+func (b *AABB) TransformBy(t *Transform) *AABB {
+	return b.Transform(t.ToMat4())
+}
+
+/*
+OBB is an oriented bounding box. The columns of Axes are its unit axes and
+HalfExtents its half size along each of them.
+*/
+type OBB struct {
+	Center      Vec3
+	Axes        Mat3
+	HalfExtents Vec3
+}
+
+func (o *OBB) FromAABB(b *AABB) *OBB {
+	return &OBB{*b.Center(), *(&Mat3{}).Identity(), *b.Extents()}
+}
+
+func (o *OBB) Volume() float64 {
+	return 8. * o.HalfExtents.X * o.HalfExtents.Y * o.HalfExtents.Z
+}
+
+func (o *OBB) SurfaceArea() float64 {
+	h := &o.HalfExtents
+	return 8. * (h.X*h.Y + h.Y*h.Z + h.Z*h.X)
+}
+
+// Coordinates of p along the box axes, relative to its center.
+func (o *OBB) local(p *Vec3) *Vec3 {
+	return p.Subtract(&o.Center).MultiplyMat3(o.Axes.Transpose())
+}
+
+func (o *OBB) ContainsPoint(p *Vec3) bool {
+	l := o.local(p)
+	return math.Abs(l.X) <= o.HalfExtents.X && math.Abs(l.Y) <= o.HalfExtents.Y && math.Abs(l.Z) <= o.HalfExtents.Z
+}
+
+func (o *OBB) ClosestPoint(p *Vec3) *Vec3 {
+	l := o.local(p).Clamp(o.HalfExtents.Negative(), &o.HalfExtents)
+	return o.Center.Add(l.MultiplyMat3(&o.Axes))
+}
+
+func (o *OBB) Corners() [8]Vec3 {
+	var c [8]Vec3
+	for i := range c {
+		l := Vec3{o.HalfExtents.X, o.HalfExtents.Y, o.HalfExtents.Z}
+		if i&1 == 0 {
+			l.X = -l.X
+		}
+		if i&2 == 0 {
+			l.Y = -l.Y
+		}
+		if i&4 == 0 {
+			l.Z = -l.Z
+		}
+		c[i] = *o.Center.Add(l.MultiplyMat3(&o.Axes))
+	}
+	return c
+}
+
+func (o *OBB) AABB() *AABB {
+	e := &Vec3{
+		boxRadius(&o.HalfExtents, o.Axes.Row(0)),
+		boxRadius(&o.HalfExtents, o.Axes.Row(1)),
+		boxRadius(&o.HalfExtents, o.Axes.Row(2)),
+	}
+	return &AABB{*o.Center.Subtract(e), *o.Center.Add(e)}
+}
+
+/*
+Transform moves the box by m. It is exact when m keeps the box axes
+perpendicular, as rotations, uniform scale and scale along the box axes do;
+otherwise the box would become a parallelepiped and only its edge lengths
+are kept.
+*/
+func (o *OBB) Transform(m *Mat4) *OBB {
+	r := &OBB{Center: *m.TransformPoint(&o.Center)}
+	h := [3]float64{o.HalfExtents.X, o.HalfExtents.Y, o.HalfExtents.Z}
+	for i := 0; i < 3; i++ {
+		a := m.TransformDirection(o.Axes.Col(i).MultiplyScalar(h[i]))
+		l := a.Magnitude()
+		h[i] = l
+		if l > 0. {
+			a = a.DivideScalar(l)
+		}
+		r.Axes.SetCol(i, a)
+	}
+	r.HalfExtents = Vec3{h[0], h[1], h[2]}
+	return r
+}
+
+func (o *OBB) TransformBy(t *Transform) *OBB {
+	return o.Transform(t.ToMat4())
+}
+
+/*
+Capsule is the set of points within Radius of the segment from A to B.
+*/
+type Capsule struct {
+	A      Vec3
+	B      Vec3
+	Radius float64
+}
+
+func (c *Capsule) Volume() float64 {
+	r := c.Radius
+	return math.Pi*r*r*c.A.Distance(&c.B) + 4./3.*math.Pi*r*r*r
+}
+
+func (c *Capsule) SurfaceArea() float64 {
+	r := c.Radius
+	return 2.*math.Pi*r*c.A.Distance(&c.B) + 4.*math.Pi*r*r
+}
+
+func (c *Capsule) ContainsPoint(p *Vec3) bool {
+	return (&Segment{c.A, c.B}).Distance(p) <= c.Radius
+}
+
+func (c *Capsule) AABB() *AABB {
+	r := &Vec3{c.Radius, c.Radius, c.Radius}
+	return &AABB{*c.A.Min(&c.B).Subtract(r), *c.A.Max(&c.B).Add(r)}
+}
+
+/*
+Transform moves the capsule by m. Under non uniform scale the result bounds
+the shape the capsule becomes.
+*/
+func (c *Capsule) Transform(m *Mat4) *Capsule {
+	return &Capsule{*m.TransformPoint(&c.A), *m.TransformPoint(&c.B), c.Radius * maxScale(m)}
+}
+
+func (c *Capsule) TransformBy(t *Transform) *Capsule {
+	return c.Transform(t.ToMat4())
+}
+
+/*
+Triangle faces the side from which A, B and C appear counter clockwise.
+*/
+type Triangle struct {
+	A Vec3
+	B Vec3
+	C Vec3
+}
+
+func (t *Triangle) Normal() *Vec3 {
+	return t.B.Subtract(&t.A).Cross(t.C.Subtract(&t.A)).Normalize()
+}
+
+func (t *Triangle) Area() float64 {
+	return 0.5 * t.B.Subtract(&t.A).Cross(t.C.Subtract(&t.A)).Magnitude()
+}
+
+func (t *Triangle) Centroid() *Vec3 {
+	return t.A.Add(&t.B).Add(&t.C).DivideScalar(3.)
+}
+
+func (t *Triangle) Plane() *Plane {
+	return (&Plane{}).FromPoints(&t.A, &t.B, &t.C)
+}
+
+/*
+Barycentric returns the weights u, v, w with p = u*A + v*B + w*C for p
+projected onto the plane of the triangle.
+*/
+func (t *Triangle) Barycentric(p *Vec3) *Vec3 {
+	e0 := t.B.Subtract(&t.A)
+	e1 := t.C.Subtract(&t.A)
+	e2 := p.Subtract(&t.A)
+	d00, d01, d11 := e0.Dot(e0), e0.Dot(e1), e1.Dot(e1)
+	d20, d21 := e2.Dot(e0), e2.Dot(e1)
+	d := d00*d11 - d01*d01
+	v := (d11*d20 - d01*d21) / d
+	w := (d00*d21 - d01*d20) / d
+	return &Vec3{1. - v - w, v, w}
+}
+
+/*
+ContainsPoint reports whether p, projected onto the plane of the triangle,
+lies inside it.
+*/
+func (t *Triangle) ContainsPoint(p *Vec3) bool {
+	b := t.Barycentric(p)
+	return b.X >= 0. && b.Y >= 0. && b.Z >= 0.
+}
+
+func (t *Triangle) AABB() *AABB {
+	return &AABB{*t.A.Min(&t.B).Min(&t.C), *t.A.Max(&t.B).Max(&t.C)}
+}
+
+func (t *Triangle) Transform(m *Mat4) *Triangle {
+	return &Triangle{*m.TransformPoint(&t.A), *m.TransformPoint(&t.B), *m.TransformPoint(&t.C)}
+}
+
+func (t *Triangle) TransformBy(tr *Transform) *Triangle {
+	return &Triangle{*tr.TransformPoint(&t.A), *tr.TransformPoint(&t.B), *tr.TransformPoint(&t.C)}
+}
@@ -0,0 +1,122 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestAABBTransform(t *testing.T) {
+	b := &mathg.AABB{Min: mathg.Vec3{-1., 0., 2.}, Max: mathg.Vec3{3., 1., 5.}}
+	tr := &mathg.Transform{
+		Translation: mathg.Vec3{1., -2., 0.5},
+		Rotation:    *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.4, -0.7, 1.1}, mathg.EulerXYZ),
+		Scale:       mathg.Vec3{2., 0.5, 1.5},
+	}
+	got := b.TransformBy(tr)
+	want := mathg.EmptyAABB()
+	o := (&mathg.OBB{}).FromAABB(b).TransformBy(tr)
+	for _, c := range o.Corners() {
+		want = want.ExpandToPoint(&c)
+	}
+	if !vec3NearlyEqual(&got.Min, &want.Min) || !vec3NearlyEqual(&got.Max, &want.Max) {
+		t.Fatalf("Transform = %v, want %v", got, want)
+	}
+	if a := o.AABB(); !vec3NearlyEqual(&a.Min, &want.Min) || !vec3NearlyEqual(&a.Max, &want.Max) {
+		t.Fatalf("OBB.AABB = %v, want %v", a, want)
+	}
+	if !mathg.NearlyEqual(o.Volume(), b.Volume()*1.5, tolerance) {
+		t.Fatalf("OBB volume = %f", o.Volume())
+	}
+	if !o.ContainsPoint(tr.TransformPoint(&mathg.Vec3{2.9, 0.1, 4.9})) || o.ContainsPoint(tr.TransformPoint(&mathg.Vec3{3.1, 0.5, 3.})) {
+		t.Fatal("OBB.ContainsPoint")
+	}
+}
+
+func TestAABBQueries(t *testing.T) {
+	b := (&mathg.AABB{}).FromPoints([]mathg.Vec3{{1., 2., 3.}, {-1., 0., 4.}, {0., 5., 2.}})
+	if b.Min != (mathg.Vec3{-1., 0., 2.}) || b.Max != (mathg.Vec3{1., 5., 4.}) {
+		t.Fatalf("FromPoints = %v", b)
+	}
+	if b.Volume() != 20. || b.SurfaceArea() != 2.*(10.+10.+4.) {
+		t.Fatalf("Volume = %f, SurfaceArea = %f", b.Volume(), b.SurfaceArea())
+	}
+	inner := &mathg.AABB{Min: mathg.Vec3{0., 1., 2.5}, Max: mathg.Vec3{0.5, 2., 3.}}
+	if b.ContainsAABB(inner) != mathg.Inside || b.ContainsAABB(inner.Expand(1.)) != mathg.Intersecting {
+		t.Fatal("ContainsAABB")
+	}
+	if !mathg.EmptyAABB().IsEmpty() || mathg.EmptyAABB().Merge(b).Volume() != 20. {
+		t.Fatal("EmptyAABB should be the identity for Merge")
+	}
+}
+
+func TestSphere(t *testing.T) {
+	points := []mathg.Vec3{{1., 0., 0.}, {-1., 0., 0.}, {0., 1., 0.}, {0., 0., -1.}, {0.3, 0.3, 0.3}}
+	s := (&mathg.Sphere{}).FromPoints(points)
+	for i := range points {
+		if s.Center.Distance(&points[i]) > s.Radius+tolerance {
+			t.Fatalf("FromPoints misses %v", points[i])
+		}
+	}
+	if s.Radius > 1.1 {
+		t.Fatalf("FromPoints radius = %f", s.Radius)
+	}
+	a := &mathg.Sphere{Center: mathg.Vec3{0., 0., 0.}, Radius: 1.}
+	b := &mathg.Sphere{Center: mathg.Vec3{4., 0., 0.}, Radius: 2.}
+	m := a.Merge(b)
+	if !vec3NearlyEqual(&m.Center, &mathg.Vec3{2.5, 0., 0.}) || !mathg.NearlyEqual(m.Radius, 3.5, tolerance) {
+		t.Fatalf("Merge = %v", m)
+	}
+	if m.ContainsSphere(a) != mathg.Inside || a.ContainsSphere(b) != mathg.Outside {
+		t.Fatal("ContainsSphere")
+	}
+	// Under shear the farthest point is not the image of an axis.
+	sheared := a.Transform((&mathg.Mat4{}).Shear(mathg.AxisX, mathg.AxisY, 1.))
+	if !mathg.NearlyEqual(sheared.Radius, (1.+math.Sqrt(5.))/2., tolerance) {
+		t.Fatalf("sheared radius = %f", sheared.Radius)
+	}
+	c := &mathg.Capsule{A: mathg.Vec3{0., 0., 0.}, B: mathg.Vec3{0., 0., 1.}, Radius: 1.}
+	if r := c.Transform((&mathg.Mat4{}).Shear(mathg.AxisX, mathg.AxisY, 1.)).Radius; r < sheared.Radius-tolerance {
+		t.Fatalf("sheared capsule radius = %f", r)
+	}
+	scaled := a.Transform((&mathg.Mat4{}).Identity().Scale(&mathg.Vec3{1., 3., 2.}))
+	if !mathg.NearlyEqual(scaled.Radius, 3., tolerance) || !mathg.NearlyEqual(a.Volume(), 4./3.*math.Pi, tolerance) {
+		t.Fatalf("scaled radius = %f", scaled.Radius)
+	}
+}
+
+func TestCapsuleAndSegment(t *testing.T) {
+	c := &mathg.Capsule{A: mathg.Vec3{0., 0., 0.}, B: mathg.Vec3{0., 2., 0.}, Radius: 0.5}
+	if !c.ContainsPoint(&mathg.Vec3{0.4, 1., 0.}) || !c.ContainsPoint(&mathg.Vec3{0., 2.4, 0.}) || c.ContainsPoint(&mathg.Vec3{0.4, 2.4, 0.}) {
+		t.Fatal("Capsule.ContainsPoint")
+	}
+	if !mathg.NearlyEqual(c.Volume(), math.Pi*0.25*2.+math.Pi/6., tolerance) {
+		t.Fatalf("Capsule volume = %f", c.Volume())
+	}
+	r := &mathg.Ray{Origin: mathg.Vec3{0., 0., 1.}, Direction: mathg.Vec3{1., 0., 0.}}
+	if p := r.ClosestPoint(&mathg.Vec3{-3., 1., 1.}); !vec3NearlyEqual(p, &r.Origin) {
+		t.Fatalf("Ray.ClosestPoint = %v", p)
+	}
+	l := (&mathg.Line{}).FromPoints(&mathg.Vec3{0., 0., 1.}, &mathg.Vec3{1., 0., 1.})
+	if !mathg.NearlyEqual(l.Distance(&mathg.Vec3{-3., 1., 1.}), 1., tolerance) {
+		t.Fatal("Line.Distance")
+	}
+}
+
+func TestTriangle(t *testing.T) {
+	tri := &mathg.Triangle{A: mathg.Vec3{0., 0., 0.}, B: mathg.Vec3{2., 0., 0.}, C: mathg.Vec3{0., 2., 0.}}
+	if !vec3NearlyEqual(tri.Normal(), &mathg.Vec3{0., 0., 1.}) || tri.Area() != 2. {
+		t.Fatal("Triangle normal or area")
+	}
+	if b := tri.Barycentric(&mathg.Vec3{0.5, 1., 3.}); !vec3NearlyEqual(b, &mathg.Vec3{0.25, 0.25, 0.5}) {
+		t.Fatalf("Barycentric = %v", b)
+	}
+	if !tri.ContainsPoint(&mathg.Vec3{0.5, 0.5, 0.}) || tri.ContainsPoint(&mathg.Vec3{1.5, 1.5, 0.}) {
+		t.Fatal("Triangle.ContainsPoint")
+	}
+	p := tri.Plane().Transform((&mathg.Mat4{}).Identity().Translation(&mathg.Vec3{0., 0., 2.}))
+	if !mathg.NearlyEqual(p.SignedDistance(&mathg.Vec3{5., 5., 3.}), 1., tolerance) {
+		t.Fatalf("Plane.Transform = %v", p)
+	}
+}
@@ -0,0 +1,162 @@
+package mathg
+
+import "math"
+
+/*
+Relative tolerance for singular matrices. A Mat2, Mat3 or Mat4 is treated as
+singular when its smallest singular value is at most this fraction of its
+largest, that is when its condition number is 1e12 or more, whatever the
+overall scale.
+*/
+const singularEpsilon float64 = 1e-12
+
+// Whether sigma is negligible next to the largest singular value max.
+func negligible(sigma, max float64) bool {
+	return !(sigma > singularEpsilon*max)
+}
+
+func isSingular(a [][]float64) bool {
+	_, s, _ := svd(a)
+	return negligible(s[len(s)-1], s[0])
+}
+
+func (m *Mat2) rows() [][]float64 {
+	return [][]float64{
+		{m.M11, m.M12},
+		{m.M21, m.M22},
+	}
+}
+
+func mat2FromRows(r [][]float64) *Mat2 {
+	return &Mat2{r[0][0], r[1][0], r[0][1], r[1][1]}
+}
+
+func (m *Mat3) rows() [][]float64 {
+	return [][]float64{
+		{m.M11, m.M12, m.M13},
+		{m.M21, m.M22, m.M23},
+		{m.M31, m.M32, m.M33},
+	}
+}
+
+func mat3FromRows(r [][]float64) *Mat3 {
+	return &Mat3{
+		r[0][0], r[1][0], r[2][0],
+		r[0][1], r[1][1], r[2][1],
+		r[0][2], r[1][2], r[2][2],
+	}
+}
+
+func (m *Mat4) rows() [][]float64 {
+	return [][]float64{
+		{m.M11, m.M12, m.M13, m.M14},
+		{m.M21, m.M22, m.M23, m.M24},
+		{m.M31, m.M32, m.M33, m.M34},
+		{m.M41, m.M42, m.M43, m.M44},
+	}
+}
+
+func mat4FromRows(r [][]float64) *Mat4 {
+	return &Mat4{
+		r[0][0], r[1][0], r[2][0], r[3][0],
+		r[0][1], r[1][1], r[2][1], r[3][1],
+		r[0][2], r[1][2], r[2][2], r[3][2],
+		r[0][3], r[1][3], r[2][3], r[3][3],
+	}
+}
+
+/*
+pseudoInverse computes V * diag(1/s) * U^T from the SVD, dropping the
+singular values that isSingular counts as negligible, so any matrix
+InverseChecked accepts gets its inverse.
+*/
+func pseudoInverse(a [][]float64) [][]float64 {
+	n := len(a)
+	u, s, v := svd(a)
+	p := make([][]float64, n)
+	for i := range p {
+		p[i] = make([]float64, n)
+	}
+	for e, sigma := range s {
+		if negligible(sigma, s[0]) {
+			continue
+		}
+		for i := 0; i < n; i++ {
+			vi := v[i][e] / sigma
+			for j := 0; j < n; j++ {
+				p[i][j] += vi * u[j][e]
+			}
+		}
+	}
+	return p
+}
+
+/*
+conditionNumber returns the ratio of the largest to the smallest singular
+value, or +Inf for a singular matrix.
+*/
+func conditionNumber(a [][]float64) float64 {
+	_, s, _ := svd(a)
+	min, max := s[len(s)-1], s[0]
+	if negligible(min, max) {
+		return math.Inf(1)
+	}
+	return max / min
+}
+
+/*
+InverseChecked returns the inverse and true, or nil and false when the
+matrix has a condition number of 1e12 or more.
+*/
+func (m *Mat2) InverseChecked() (*Mat2, bool) {
+	if isSingular(m.rows()) {
+		return nil, false
+	}
+	return m.Inverse(), true
+}
+
+func (m *Mat2) PseudoInverse() *Mat2 {
+	return mat2FromRows(pseudoInverse(m.rows()))
+}
+
+func (m *Mat2) ConditionNumber() float64 {
+	return conditionNumber(m.rows())
+}
+
+/*
+InverseChecked returns the inverse and true, or nil and false when the
+matrix has a condition number of 1e12 or more.
+*/
+func (m *Mat3) InverseChecked() (*Mat3, bool) {
+	if isSingular(m.rows()) {
+		return nil, false
+	}
+	return m.Inverse(), true
+}
+
+func (m *Mat3) PseudoInverse() *Mat3 {
+	return mat3FromRows(pseudoInverse(m.rows()))
+}
+
+func (m *Mat3) ConditionNumber() float64 {
+	return conditionNumber(m.rows())
+}
+
+/*
+InverseChecked returns the inverse and true, or nil and false when the
+matrix has a condition number of 1e12 or more.
+*/
+func (m *Mat4) InverseChecked() (*Mat4, bool) {
+	if isSingular(m.rows()) {
+		return nil, false
+	}
+	return m.Inverse(), true
+}
+
+func (m *Mat4) PseudoInverse() *Mat4 {
+	return mat4FromRows(pseudoInverse(m.rows()))
+}
+
+func (m *Mat4) ConditionNumber() float64 {
+	return conditionNumber(m.rows())
+}
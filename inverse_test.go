@@ -0,0 +1,100 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestMat2InverseChecked(t *testing.T) {
+	m := &mathg.Mat2{1., 2., 3., 5.}
+	i, ok := m.InverseChecked()
+	if !ok {
+		t.Fatal("InverseChecked rejected an invertible Mat2")
+	}
+	p := m.Multiply(i)
+	if !mathg.NearlyEqual(p.M11, 1., tolerance) || !mathg.NearlyEqual(p.M21, 0., tolerance) ||
+		!mathg.NearlyEqual(p.M12, 0., tolerance) || !mathg.NearlyEqual(p.M22, 1., tolerance) {
+		t.Fatalf("Mat2 Inverse product = %v", *p)
+	}
+}
+
+func TestMat3InverseCheckedSingular(t *testing.T) {
+	m := (&mathg.Mat3{}).Identity().Scale(&mathg.Vec3{1e6, 1e6, 0.})
+	if _, ok := m.InverseChecked(); ok {
+		t.Fatal("InverseChecked accepted a singular Mat3")
+	}
+	small := (&mathg.Mat3{}).Identity().MultiplyScalar(1e-5)
+	if _, ok := small.InverseChecked(); !ok {
+		t.Fatal("InverseChecked rejected a small but well conditioned Mat3")
+	}
+}
+
+func TestMat4PseudoInverse(t *testing.T) {
+	m := (&mathg.Mat4{}).Identity().Scale(&mathg.Vec3{2., 0., 4.})
+	if _, ok := m.InverseChecked(); ok {
+		t.Fatal("InverseChecked accepted a singular Mat4")
+	}
+	p := m.PseudoInverse()
+	want := (&mathg.Mat4{}).Identity().Scale(&mathg.Vec3{0.5, 0., 0.25})
+	if !mat4NearlyEqual(p, want) {
+		t.Fatalf("PseudoInverse = %v", *p)
+	}
+	if !math.IsInf(m.ConditionNumber(), 1) {
+		t.Fatal("ConditionNumber of a singular Mat4 is finite")
+	}
+}
+
+func TestMat3ConditionNumber(t *testing.T) {
+	m := (&mathg.Mat3{}).Identity().Scale(&mathg.Vec3{1., 10., 100.})
+	if !mathg.NearlyEqual(m.ConditionNumber(), 100., 1e-6) {
+		t.Fatalf("ConditionNumber = %f", m.ConditionNumber())
+	}
+}
+
+func TestPseudoInverseMatchesInverse(t *testing.T) {
+	m := (&mathg.Mat4{}).Identity().Scale(&mathg.Vec3{1., 1e-4, 1e3})
+	if p := m.PseudoInverse(); !mathg.NearlyEqual(p.M22, 1e4, 1e-6) {
+		t.Fatalf("PseudoInverse M22 = %g, want 1e4", p.M22)
+	}
+	// Condition number around 1e7 behind two rotations.
+	a := (&mathg.Mat3{}).FromEuler(&mathg.Vec3{0.3, -0.4, 1.}, mathg.EulerXYZ)
+	b := (&mathg.Mat3{}).FromEuler(&mathg.Vec3{-1.2, 0.7, 0.2}, mathg.EulerZYX)
+	m3 := a.Multiply((&mathg.Mat3{}).Identity().Scale(&mathg.Vec3{10., 1., 1e-6})).Multiply(b.Transpose())
+	if c := m3.ConditionNumber(); math.Abs(c/1e7-1.) > 1e-6 {
+		t.Fatalf("ConditionNumber = %g", c)
+	}
+	inv, ok := m3.InverseChecked()
+	if !ok {
+		t.Fatal("InverseChecked rejected a condition number of 1e7")
+	}
+	p := m3.PseudoInverse()
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(p.At(i, j)-inv.At(i, j)) > 1e-9*1e6 {
+				t.Fatalf("PseudoInverse = %v, Inverse = %v", *p, *inv)
+			}
+		}
+	}
+}
+
+func TestInverseCheckedMatchesPseudoInverse(t *testing.T) {
+	// The determinant of a badly scaled diagonal matrix is far above the
+	// product of its column lengths times any tolerance, but the ratio of its
+	// singular values is not.
+	for _, d := range []float64{1e-9, 1e-11, 1e-13, 1e-15} {
+		m := (&mathg.Mat3{}).Identity().Scale(&mathg.Vec3{1., d, 1.})
+		p := m.PseudoInverse()
+		inv, ok := m.InverseChecked()
+		if ok != (p.M22 != 0.) {
+			t.Fatalf("diag(1, %g, 1): InverseChecked ok = %v, PseudoInverse M22 = %g", d, ok, p.M22)
+		}
+		if ok && !mathg.NearlyEqual(p.M22/inv.M22, 1., tolerance) {
+			t.Fatalf("diag(1, %g, 1): PseudoInverse M22 = %g, Inverse M22 = %g", d, p.M22, inv.M22)
+		}
+	}
+	if _, ok := (&mathg.Mat2{1., 0., 0., 1e-13}).InverseChecked(); ok {
+		t.Fatal("InverseChecked accepted diag(1, 1e-13)")
+	}
+}
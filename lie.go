@@ -0,0 +1,172 @@
+package mathg
+
+import "math"
+
+// Below this angle the Lie group maps switch to Taylor expansions.
+const lieSmallAngle float64 = 1e-6
+
+/*
+Twist is an element of the tangent space of SE(3): a linear velocity and an
+angular velocity, or a translation and rotation vector when used with
+ExpSE3 and LogSE3.
+*/
+type Twist struct {
+	Linear  Vec3
+	Angular Vec3
+}
+
+/*
+Hat returns the skew symmetric matrix of v, so that v.Hat() * u equals
+v.Cross(u).
+*/
+func (v *Vec3) Hat() *Mat3 {
+	return &Mat3{
+		0., v.Z, -v.Y,
+		-v.Z, 0., v.X,
+		v.Y, -v.X, 0.,
+	}
+}
+
+/*
+Vee is the inverse of Vec3.Hat. Only the skew symmetric part of m is used.
+*/
+func (m *Mat3) Vee() *Vec3 {
+	return &Vec3{
+		0.5 * (m.M32 - m.M23),
+		0.5 * (m.M13 - m.M31),
+		0.5 * (m.M21 - m.M12),
+	}
+}
+
+func (t *Twist) Hat() *Mat4 {
+	w := t.Angular.Hat()
+	return &Mat4{
+		w.M11, w.M21, w.M31, 0.,
+		w.M12, w.M22, w.M32, 0.,
+		w.M13, w.M23, w.M33, 0.,
+		t.Linear.X, t.Linear.Y, t.Linear.Z, 0.,
+	}
+}
+
+func (m *Mat4) Vee() *Twist {
+	w := m.Mat3().Vee()
+	return &Twist{Vec3{m.M14, m.M24, m.M34}, *w}
+}
+
+// a * I + b * W + c * W^2
+func so3Series(omega *Vec3, a, b, c float64) *Mat3 {
+	w := omega.Hat()
+	w2 := w.Multiply(w)
+	return &Mat3{
+		a + b*w.M11 + c*w2.M11,
+		b*w.M21 + c*w2.M21,
+		b*w.M31 + c*w2.M31,
+		b*w.M12 + c*w2.M12,
+		a + b*w.M22 + c*w2.M22,
+		b*w.M32 + c*w2.M32,
+		b*w.M13 + c*w2.M13,
+		b*w.M23 + c*w2.M23,
+		a + b*w.M33 + c*w2.M33,
+	}
+}
+
+/*
+ExpSO3 returns the rotation matrix that turns by |omega| radians about
+omega (Rodrigues' formula).
+*/
+func ExpSO3(omega *Vec3) *Mat3 {
+	t2 := omega.LengthSquared()
+	t := math.Sqrt(t2)
+	if t < lieSmallAngle {
+		return so3Series(omega, 1., 1.-t2/6., 0.5-t2/24.)
+	}
+	return so3Series(omega, 1., math.Sin(t)/t, (1.-math.Cos(t))/t2)
+}
+
+/*
+LogSO3 returns the rotation vector of the rotation matrix r, with a length
+in [0, pi].
+*/
+func LogSO3(r *Mat3) *Vec3 {
+	c := Clamp((r.M11+r.M22+r.M33-1.)*0.5, -1., 1.)
+	t := math.Acos(c)
+	if t < lieSmallAngle {
+		return r.Vee().MultiplyScalar(1. + t*t/6.)
+	}
+	if math.Pi-t < 1e-4 {
+		// sin(t) vanishes, so recover the axis from the symmetric part
+		// (R + I) / 2 = a a^T + O(pi - t) instead.
+		var a *Vec3
+		if r.M11 >= r.M22 && r.M11 >= r.M33 {
+			a = &Vec3{r.M11 + 1., (r.M21 + r.M12) * 0.5, (r.M31 + r.M13) * 0.5}
+		} else if r.M22 >= r.M33 {
+			a = &Vec3{(r.M12 + r.M21) * 0.5, r.M22 + 1., (r.M32 + r.M23) * 0.5}
+		} else {
+			a = &Vec3{(r.M13 + r.M31) * 0.5, (r.M23 + r.M32) * 0.5, r.M33 + 1.}
+		}
+		a = a.Normalize()
+		if a.Dot(r.Vee()) < 0. {
+			a = a.Negative()
+		}
+		return a.MultiplyScalar(t)
+	}
+	return r.Vee().MultiplyScalar(t / math.Sin(t))
+}
+
+/*
+LeftJacobianSO3 maps a perturbation of omega to the matching perturbation of
+ExpSO3(omega) applied on the left.
+*/
+func LeftJacobianSO3(omega *Vec3) *Mat3 {
+	t2 := omega.LengthSquared()
+	t := math.Sqrt(t2)
+	if t < lieSmallAngle {
+		return so3Series(omega, 1., 0.5-t2/24., 1./6.-t2/120.)
+	}
+	return so3Series(omega, 1., (1.-math.Cos(t))/t2, (t-math.Sin(t))/(t2*t))
+}
+
+func LeftJacobianInverseSO3(omega *Vec3) *Mat3 {
+	t2 := omega.LengthSquared()
+	t := math.Sqrt(t2)
+	if t < lieSmallAngle {
+		return so3Series(omega, 1., -0.5, 1./12.+t2/720.)
+	}
+	return so3Series(omega, 1., -0.5, 1./t2-(1.+math.Cos(t))/(2.*t*math.Sin(t)))
+}
+
+func RightJacobianSO3(omega *Vec3) *Mat3 {
+	return LeftJacobianSO3(omega.Negative())
+}
+
+func RightJacobianInverseSO3(omega *Vec3) *Mat3 {
+	return LeftJacobianInverseSO3(omega.Negative())
+}
+
+/*
+ExpSE3 returns the rigid transform generated by the twist t, with rotation
+ExpSO3(t.Angular) and translation LeftJacobianSO3(t.Angular) * t.Linear.
+*/
+func ExpSE3(t *Twist) *Mat4 {
+	p := t.Linear.MultiplyMat3(LeftJacobianSO3(&t.Angular))
+	return ExpSO3(&t.Angular).ToMat4().Translation(p)
+}
+
+/*
+LogSE3 is the inverse of ExpSE3 for a matrix made of rotation and
+translation only.
+*/
+func LogSE3(m *Mat4) *Twist {
+	w := LogSO3(m.Mat3())
+	v := (&Vec3{m.M14, m.M24, m.M34}).MultiplyMat3(LeftJacobianInverseSO3(w))
+	return &Twist{*v, *w}
+}
+
+/*
+Orthonormalize returns the rotation closest to m, which removes the scale
+and skew that accumulate when rotations are multiplied repeatedly.
+*/
+func (m *Mat3) Orthonormalize() *Mat3 {
+	r, _ := m.PolarDecompose()
+	return r
+}
@@ -0,0 +1,47 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestExpLogSO3(t *testing.T) {
+	for _, w := range []*mathg.Vec3{{0.3, -0.2, 0.9}, {1e-9, 0., 2e-9}, {0., math.Pi - 1e-7, 0.}, {0.6, 0.8, 0.}} {
+		r := mathg.ExpSO3(w)
+		q := w.Normalize().ToVec4().ToQuaternionFromAxisAngle(w.Magnitude())
+		if w.Magnitude() > 0. && !mat3NearlyEqual(r, q.RotationMatrix()) {
+			t.Fatalf("ExpSO3(%v) does not match the axis angle rotation", *w)
+		}
+		back := mathg.LogSO3(r)
+		if !mathg.NearlyEqual(back.X, w.X, 1e-6) || !mathg.NearlyEqual(back.Y, w.Y, 1e-6) || !mathg.NearlyEqual(back.Z, w.Z, 1e-6) {
+			t.Fatalf("LogSO3(ExpSO3(%v)) = %v", *w, *back)
+		}
+	}
+}
+
+func TestExpLogSE3(t *testing.T) {
+	tw := &mathg.Twist{Linear: mathg.Vec3{1., -2., 0.5}, Angular: mathg.Vec3{0.4, 0.1, -0.7}}
+	m := mathg.ExpSE3(tw)
+	back := mathg.LogSE3(m)
+	if !vec3NearlyEqual(&back.Linear, &tw.Linear) || !vec3NearlyEqual(&back.Angular, &tw.Angular) {
+		t.Fatalf("LogSE3(ExpSE3(%v)) = %v", *tw, *back)
+	}
+}
+
+func TestLeftJacobianInverse(t *testing.T) {
+	w := &mathg.Vec3{0.5, -1.2, 0.3}
+	p := mathg.LeftJacobianSO3(w).Multiply(mathg.LeftJacobianInverseSO3(w))
+	if !mat3NearlyEqual(p, (&mathg.Mat3{}).Identity()) {
+		t.Fatalf("J * J^-1 = %v", *p)
+	}
+}
+
+func TestHatVee(t *testing.T) {
+	v := &mathg.Vec3{1., 2., 3.}
+	u := &mathg.Vec3{-0.5, 4., 2.}
+	if !vec3NearlyEqual(u.MultiplyMat3(v.Hat()), v.Cross(u)) || !vec3NearlyEqual(v.Hat().Vee(), v) {
+		t.Fatal("Hat and Vee are inconsistent with Cross")
+	}
+}
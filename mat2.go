@@ -56,7 +56,7 @@ func (m *Mat2) MultiplyScalar(scalar float64) *Mat2 {
 
 func (m *Mat2) Inverse() *Mat2 {
 	det := m.Determinant()
-	inverse := m.Cofactor()
+	inverse := m.Adjugate()
 	inverse = inverse.MultiplyScalar(1. / det)
 	return inverse
 }
@@ -0,0 +1,18 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestMat2Inverse(t *testing.T) {
+	m := &mathg.Mat2{2., 1., 4., 3.}
+	inv := m.Inverse()
+	if want := (&mathg.Mat2{1.5, -0.5, -2., 1.}); *inv != *want {
+		t.Fatalf("Inverse = %v, want %v", *inv, *want)
+	}
+	if p := m.Multiply(inv); *p != *(&mathg.Mat2{}).Identity() {
+		t.Fatalf("m * m^-1 = %v", *p)
+	}
+}
@@ -236,15 +236,15 @@ func (q *Quaternion) RotationMatrix() *Mat3 {
 	yz := q.Y * q.Z
 	xw := q.X * q.W
 	return &Mat3{
-		1. - 2.*(yy-zz),
+		1. - 2.*(yy+zz),
 		2. * (xy + zw),
 		2. * (xz - yw),
 		2. * (xy - zw),
-		1. - 2.*(xx-zz),
+		1. - 2.*(xx+zz),
+		2. * (yz + xw),
+		2. * (xz + yw),
 		2. * (yz - xw),
-		2. * (xz - yw),
-		2. * (yz - xw),
-		1. - 2.*(xx-yy),
+		1. - 2.*(xx+yy),
 	}
 }
 
@@ -272,7 +272,7 @@ func (q *Quaternion) RotationMatrix4() *Mat4 {
 	zz := q.Z * q.Z
 	xy := q.X * q.Y
 	xz := q.X * q.Z
-	xw := q.W * q.W
+	xw := q.X * q.W
 	yz := q.Y * q.Z
 	yw := q.Y * q.W
 	zw := q.Z * q.W
@@ -334,22 +334,22 @@ func (m *Mat4) Scale(v *Vec3) *Mat4 {
 
 func (m *Mat4) Multiply(m1 *Mat4) *Mat4 {
 	return &Mat4{
-		m.M11*m1.M11 + m.M12*m1.M21 + m.M13*m1.M31 + m.M14*m.M41,
-		m.M21*m1.M11 + m.M22*m1.M21 + m.M23*m1.M31 + m.M24*m.M41,
-		m.M31*m1.M11 + m.M32*m1.M21 + m.M33*m1.M31 + m.M34*m.M41,
-		m.M41*m1.M11 + m.M42*m1.M21 + m.M43*m1.M31 + m.M44*m.M41,
-		m.M11*m1.M12 + m.M12*m1.M22 + m.M13*m1.M32 + m.M14*m.M42,
-		m.M21*m1.M12 + m.M22*m1.M22 + m.M23*m1.M32 + m.M24*m.M42,
-		m.M31*m1.M12 + m.M32*m1.M22 + m.M33*m1.M32 + m.M34*m.M42,
-		m.M41*m1.M12 + m.M42*m1.M22 + m.M43*m1.M32 + m.M44*m.M42,
-		m.M11*m1.M13 + m.M12*m1.M23 + m.M13*m1.M33 + m.M14*m.M43,
-		m.M21*m1.M13 + m.M22*m1.M23 + m.M23*m1.M33 + m.M24*m.M43,
-		m.M31*m1.M13 + m.M32*m1.M23 + m.M33*m1.M33 + m.M34*m.M43,
-		m.M41*m1.M13 + m.M42*m1.M23 + m.M43*m1.M33 + m.M44*m.M43,
-		m.M11*m1.M14 + m.M12*m1.M24 + m.M13*m1.M34 + m.M14*m.M44,
-		m.M21*m1.M14 + m.M22*m1.M24 + m.M23*m1.M34 + m.M24*m.M44,
-		m.M31*m1.M14 + m.M32*m1.M24 + m.M33*m1.M34 + m.M34*m.M44,
-		m.M41*m1.M14 + m.M42*m1.M24 + m.M43*m1.M34 + m.M44*m.M44,
+		m.M11*m1.M11 + m.M12*m1.M21 + m.M13*m1.M31 + m.M14*m1.M41,
+		m.M21*m1.M11 + m.M22*m1.M21 + m.M23*m1.M31 + m.M24*m1.M41,
+		m.M31*m1.M11 + m.M32*m1.M21 + m.M33*m1.M31 + m.M34*m1.M41,
+		m.M41*m1.M11 + m.M42*m1.M21 + m.M43*m1.M31 + m.M44*m1.M41,
+		m.M11*m1.M12 + m.M12*m1.M22 + m.M13*m1.M32 + m.M14*m1.M42,
+		m.M21*m1.M12 + m.M22*m1.M22 + m.M23*m1.M32 + m.M24*m1.M42,
+		m.M31*m1.M12 + m.M32*m1.M22 + m.M33*m1.M32 + m.M34*m1.M42,
+		m.M41*m1.M12 + m.M42*m1.M22 + m.M43*m1.M32 + m.M44*m1.M42,
+		m.M11*m1.M13 + m.M12*m1.M23 + m.M13*m1.M33 + m.M14*m1.M43,
+		m.M21*m1.M13 + m.M22*m1.M23 + m.M23*m1.M33 + m.M24*m1.M43,
+		m.M31*m1.M13 + m.M32*m1.M23 + m.M33*m1.M33 + m.M34*m1.M43,
+		m.M41*m1.M13 + m.M42*m1.M23 + m.M43*m1.M33 + m.M44*m1.M43,
+		m.M11*m1.M14 + m.M12*m1.M24 + m.M13*m1.M34 + m.M14*m1.M44,
+		m.M21*m1.M14 + m.M22*m1.M24 + m.M23*m1.M34 + m.M24*m1.M44,
+		m.M31*m1.M14 + m.M32*m1.M24 + m.M33*m1.M34 + m.M34*m1.M44,
+		m.M41*m1.M14 + m.M42*m1.M24 + m.M43*m1.M34 + m.M44*m1.M44,
 	}
 }
 
@@ -405,7 +405,7 @@ func (pos *Vec3) LookAt(target *Vec3, up *Vec3) *Mat4 {
 }
 
 /*
-left, right, bottom, top, near, far
+left, right, bottom, top, near, far. Right handed with [-1, 1] depth.
 */
 func Ortho(l, r, b, t, n, f float64) *Mat4 {
 	return &Mat4{
@@ -416,27 +416,38 @@ func Ortho(l, r, b, t, n, f float64) *Mat4 {
 	}
 }
 
+/*
+Perspective is right handed with [0, 1] depth. Use ClipSpace.Perspective to
+choose the convention.
+*/
 func Perspective(fov_y, aspect, near, far float64) *Mat4 {
 	tan_half_fov_y := 1. / math.Tan(fov_y*0.5)
 	return &Mat4{
 		1. / aspect * tan_half_fov_y, 0., 0., 0.,
-		0., 1. / tan_half_fov_y, 0., 0.,
+		0., tan_half_fov_y, 0., 0.,
 		0., 0., far / (near - far), -1.,
 		0., 0., -(far * near) / (far - near), 0.,
 	}
 }
 
+/*
+PerspectiveFOV is left handed with [0, 1] depth, taking the field of view
+and the width and height of the target.
+*/
 func PerspectiveFOV(fov, w, h, n, f float64) *Mat4 {
 	h2 := math.Cos(fov*0.5) / math.Sin(fov*0.5)
 	w2 := h2 * h / w
 	return &Mat4{
 		w2, 0., 0., 0.,
 		0., h2, 0., 0.,
-		0., 0., f / (n - f), 1.,
+		0., 0., f / (f - n), 1.,
 		0., 0., -(f * n) / (f - n), 0.,
 	}
 }
 
+/*
+PerspectiveInfinite is right handed with [-1, 1] depth.
+*/
 func PerspectiveInfinite(fov_y, aspect, near float64) *Mat4 {
 	rng := math.Tan(fov_y*0.5) * near
 	left := -rng * aspect
@@ -450,3 +461,63 @@ func PerspectiveInfinite(fov_y, aspect, near float64) *Mat4 {
 		0., 0., -2. * near, 0.,
 	}
 }
+
+/*
+NormalMatrix returns the inverse transpose of the upper 3x3, which maps
+surface normals consistently with the directions transformed by m.
+*/
+func (m *Mat4) NormalMatrix() *Mat3 {
+	return m.Mat3().Inverse().Transpose()
+}
+
+/*
+TransformPoint applies m to v with w = 1 and divides by the resulting w, so
+it also works for projection matrices. A point that lands on w = 0, such as
+one on the camera plane of a perspective projection, is at infinity and
+comes back with infinite or NaN coordinates.
+*/
+func (m *Mat4) TransformPoint(v *Vec3) *Vec3 {
+	w := m.M41*v.X + m.M42*v.Y + m.M43*v.Z + m.M44
+	return &Vec3{
+		(m.M11*v.X + m.M12*v.Y + m.M13*v.Z + m.M14) / w,
+		(m.M21*v.X + m.M22*v.Y + m.M23*v.Z + m.M24) / w,
+		(m.M31*v.X + m.M32*v.Y + m.M33*v.Z + m.M34) / w,
+	}
+}
+
+/*
+TransformDirection applies the upper 3x3 of m to v, ignoring translation.
+*/
+func (m *Mat4) TransformDirection(v *Vec3) *Vec3 {
+	return &Vec3{
+		m.M11*v.X + m.M12*v.Y + m.M13*v.Z,
+		m.M21*v.X + m.M22*v.Y + m.M23*v.Z,
+		m.M31*v.X + m.M32*v.Y + m.M33*v.Z,
+	}
+}
+
+/*
+TransformNormal transforms the surface normal n and returns it normalized.
+It uses the cofactor matrix, which is the normal matrix scaled by the
+determinant, so it stays defined when the upper 3x3 is singular.
+*/
+func (m *Mat4) TransformNormal(n *Vec3) *Vec3 {
+	r := m.Mat3()
+	v := n.MultiplyMat3(r.Cofactor())
+	if r.Determinant() < 0. {
+		v = v.Negative()
+	}
+	l := v.Magnitude()
+	if l == 0. {
+		return v
+	}
+	return v.DivideScalar(l)
+}
+
+/*
+TransformPlane transforms the plane a*x + b*y + c*z + d = 0, stored as
+{a, b, c, d}, by the inverse transpose of m.
+*/
+func (m *Mat4) TransformPlane(plane *Vec4) *Vec4 {
+	return plane.MultiplyMat4(m.Inverse().Transpose())
+}
@@ -0,0 +1,86 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestMat4Multiply(t *testing.T) {
+	// Both have a projective fourth row, which the product must read from b.
+	a := &mathg.Mat4{1., 2., 0., 0.5, -1., 3., 1., 0., 0., 1., 2., -1., 4., 0., 1., 2.}
+	b := &mathg.Mat4{2., 0., 1., 1., 1., -1., 0., 2., 0., 3., 1., 0., -2., 1., 0., 1.}
+	v := &mathg.Vec4{0.5, -1., 2., 1.}
+	got := v.MultiplyMat4(a.Multiply(b))
+	want := v.MultiplyMat4(b).MultiplyMat4(a)
+	if !got.IsEqual(want) {
+		t.Fatalf("(a * b) v = %v, want a (b v) = %v", *got, *want)
+	}
+}
+
+func TestMat4TransformPoint(t *testing.T) {
+	m := (&mathg.Transform{
+		Translation: mathg.Vec3{1., -2., 3.},
+		Rotation:    *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.3, 0.5, -1.2}, mathg.EulerXYZ),
+		Scale:       mathg.Vec3{2., 0.5, 1.5},
+	}).ToMat4()
+	v := &mathg.Vec3{0.4, -1., 2.}
+	p := (&mathg.Vec4{v.X, v.Y, v.Z, 1.}).MultiplyMat4(m)
+	if !vec3NearlyEqual(m.TransformPoint(v), &mathg.Vec3{p.X, p.Y, p.Z}) {
+		t.Fatal("TransformPoint disagrees with MultiplyMat4")
+	}
+	d := (&mathg.Vec4{v.X, v.Y, v.Z, 0.}).MultiplyMat4(m)
+	if !vec3NearlyEqual(m.TransformDirection(v), &mathg.Vec3{d.X, d.Y, d.Z}) {
+		t.Fatal("TransformDirection should ignore translation")
+	}
+	proj := mathg.Perspective(1., 1., 1., 10.)
+	if q := proj.TransformPoint(&mathg.Vec3{0., 0., -10.}); !mathg.NearlyEqual(q.Z, 1., tolerance) {
+		t.Fatalf("TransformPoint did not divide by w: %v", *q)
+	}
+	if q := proj.TransformPoint(&mathg.Vec3{1., 0., 0.}); !math.IsInf(q.X, 1) {
+		t.Fatalf("a point on the camera plane should go to infinity, got %v", *q)
+	}
+}
+
+func TestMat4TransformNormal(t *testing.T) {
+	for _, scale := range []*mathg.Vec3{{2., 0.5, 3.}, {-2., 0.5, 3.}} {
+		m := (&mathg.Transform{
+			Translation: mathg.Vec3{1., -2., 3.},
+			Rotation:    *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.3, 0.5, -1.2}, mathg.EulerXYZ),
+			Scale:       *scale,
+		}).ToMat4()
+		// Tangents of the plane x + y + z = 0 and its normal.
+		t1, t2 := &mathg.Vec3{1., -1., 0.}, &mathg.Vec3{0., 1., -1.}
+		n := t1.Cross(t2)
+		a, b := m.TransformDirection(t1), m.TransformDirection(t2)
+		// A mirror flips the winding of the tangents but the normal must keep
+		// pointing to the same side of the surface.
+		want := a.Cross(b).Normalize()
+		if m.Determinant() < 0. {
+			want = want.Negative()
+		}
+		if got := m.TransformNormal(n); !vec3NearlyEqual(got, want) {
+			t.Fatalf("scale %v: TransformNormal = %v, want %v", *scale, *got, *want)
+		}
+		if got := n.MultiplyMat3(m.NormalMatrix()); !mathg.NearlyEqual(got.Dot(a), 0., tolerance) || !mathg.NearlyEqual(got.Dot(b), 0., tolerance) {
+			t.Fatal("NormalMatrix does not keep normals perpendicular")
+		}
+	}
+}
+
+func TestMat4TransformPlane(t *testing.T) {
+	m := (&mathg.Transform{
+		Translation: mathg.Vec3{1., -2., 3.},
+		Rotation:    *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.3, 0.5, -1.2}, mathg.EulerXYZ),
+		Scale:       mathg.Vec3{2., 0.5, 1.5},
+	}).ToMat4()
+	plane := &mathg.Vec4{1., 2., -1., 3.}
+	q := m.TransformPlane(plane)
+	for _, p := range []*mathg.Vec3{{-3., 0., 0.}, {0., -1., 1.}, {1., -1., 2.}} {
+		w := m.TransformPoint(p)
+		if d := q.X*w.X + q.Y*w.Y + q.Z*w.Z + q.W; !mathg.NearlyEqual(d, 0., tolerance) {
+			t.Fatalf("transformed point %v is %f off the transformed plane", *w, d)
+		}
+	}
+}
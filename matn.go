@@ -0,0 +1,563 @@
+package mathg
+
+import "math"
+
+/*
+MatN is a dense matrix of any size stored in row-major order, so the
+element at row i and column j is Data[i*Cols+j]. Operations panic when the
+dimensions do not match, like out of range slice indexing.
+*/
+type MatN struct {
+	Rows int
+	Cols int
+	Data []float64
+}
+
+func NewMatN(rows, cols int) *MatN {
+	return &MatN{rows, cols, make([]float64, rows*cols)}
+}
+
+func NewMatNIdentity(n int) *MatN {
+	m := NewMatN(n, n)
+	for i := 0; i < n; i++ {
+		m.Data[i*n+i] = 1.
+	}
+	return m
+}
+
+/*
+NewMatNFromRows copies the given rows, which must all have the same length.
+*/
+func NewMatNFromRows(rows [][]float64) *MatN {
+	if len(rows) == 0 {
+		return NewMatN(0, 0)
+	}
+	m := NewMatN(len(rows), len(rows[0]))
+	for i, r := range rows {
+		if len(r) != m.Cols {
+			panic("mathg: NewMatNFromRows rows have different lengths")
+		}
+		copy(m.Data[i*m.Cols:], r)
+	}
+	return m
+}
+
+func (m *MatN) At(i, j int) float64 {
+	return m.Data[i*m.Cols+j]
+}
+
+func (m *MatN) Set(i, j int, v float64) {
+	m.Data[i*m.Cols+j] = v
+}
+
+func (m *MatN) Clone() *MatN {
+	c := NewMatN(m.Rows, m.Cols)
+	copy(c.Data, m.Data)
+	return c
+}
+
+func (m *MatN) IsSquare() bool {
+	return m.Rows == m.Cols
+}
+
+func (m *MatN) Transpose() *MatN {
+	t := NewMatN(m.Cols, m.Rows)
+	for i := 0; i < m.Rows; i++ {
+		for j := 0; j < m.Cols; j++ {
+			t.Data[j*m.Rows+i] = m.Data[i*m.Cols+j]
+		}
+	}
+	return t
+}
+
+func (m *MatN) Add(m1 *MatN) *MatN {
+	if m.Rows != m1.Rows || m.Cols != m1.Cols {
+		panic("mathg: MatN.Add dimension mismatch")
+	}
+	r := NewMatN(m.Rows, m.Cols)
+	for i := range r.Data {
+		r.Data[i] = m.Data[i] + m1.Data[i]
+	}
+	return r
+}
+
+func (m *MatN) MultiplyScalar(scalar float64) *MatN {
+	r := NewMatN(m.Rows, m.Cols)
+	for i := range r.Data {
+		r.Data[i] = m.Data[i] * scalar
+	}
+	return r
+}
+
+func (m *MatN) Multiply(m1 *MatN) *MatN {
+	if m.Cols != m1.Rows {
+		panic("mathg: MatN.Multiply dimension mismatch")
+	}
+	r := NewMatN(m.Rows, m1.Cols)
+	for i := 0; i < m.Rows; i++ {
+		for k := 0; k < m.Cols; k++ {
+			a := m.Data[i*m.Cols+k]
+			if a == 0. {
+				continue
+			}
+			for j := 0; j < m1.Cols; j++ {
+				r.Data[i*r.Cols+j] += a * m1.Data[k*m1.Cols+j]
+			}
+		}
+	}
+	return r
+}
+
+func (m *MatN) MultiplyVec(v []float64) []float64 {
+	if m.Cols != len(v) {
+		panic("mathg: MatN.MultiplyVec dimension mismatch")
+	}
+	r := make([]float64, m.Rows)
+	for i := 0; i < m.Rows; i++ {
+		s := 0.
+		for j, x := range v {
+			s += m.Data[i*m.Cols+j] * x
+		}
+		r[i] = s
+	}
+	return r
+}
+
+func (m *MatN) maxAbs() float64 {
+	max := 0.
+	for _, x := range m.Data {
+		max = math.Max(max, math.Abs(x))
+	}
+	return max
+}
+
+/*
+LU holds the factorization P * A = L * U of a square matrix, with L unit
+lower triangular and U upper triangular packed into one matrix.
+*/
+type LU struct {
+	lu       *MatN
+	pivot    []int
+	sign     float64
+	singular bool
+}
+
+/*
+LU factorizes a square matrix with partial pivoting. ok is false when a
+pivot is negligible compared to the largest element, in which case the
+factorization can still report a determinant but cannot solve.
+*/
+func (m *MatN) LU() (lu *LU, ok bool) {
+	if !m.IsSquare() {
+		panic("mathg: MatN.LU of a non square matrix")
+	}
+	n := m.Rows
+	a := m.Clone()
+	lu = &LU{a, make([]int, n), 1., false}
+	for i := range lu.pivot {
+		lu.pivot[i] = i
+	}
+	tiny := singularEpsilon * m.maxAbs()
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a.Data[i*n+k]) > math.Abs(a.Data[p*n+k]) {
+				p = i
+			}
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				a.Data[p*n+j], a.Data[k*n+j] = a.Data[k*n+j], a.Data[p*n+j]
+			}
+			lu.pivot[p], lu.pivot[k] = lu.pivot[k], lu.pivot[p]
+			lu.sign = -lu.sign
+		}
+		d := a.Data[k*n+k]
+		if math.Abs(d) <= tiny || d == 0. {
+			lu.singular = true
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			a.Data[i*n+k] /= d
+			f := a.Data[i*n+k]
+			for j := k + 1; j < n; j++ {
+				a.Data[i*n+j] -= f * a.Data[k*n+j]
+			}
+		}
+	}
+	return lu, !lu.singular
+}
+
+func (lu *LU) Determinant() float64 {
+	n := lu.lu.Rows
+	d := lu.sign
+	for i := 0; i < n; i++ {
+		d *= lu.lu.Data[i*n+i]
+	}
+	return d
+}
+
+func (lu *LU) Solve(b []float64) ([]float64, bool) {
+	n := lu.lu.Rows
+	if len(b) != n {
+		panic("mathg: LU.Solve dimension mismatch")
+	}
+	if lu.singular {
+		return nil, false
+	}
+	a := lu.lu.Data
+	x := make([]float64, n)
+	for i, p := range lu.pivot {
+		x[i] = b[p]
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			x[i] -= a[i*n+j] * x[j]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= a[i*n+j] * x[j]
+		}
+		x[i] /= a[i*n+i]
+	}
+	return x, true
+}
+
+/*
+QR holds the Householder factorization A = Q * R of a matrix with at least
+as many rows as columns.
+*/
+type QR struct {
+	qr    *MatN
+	rdiag []float64
+}
+
+func (m *MatN) QR() *QR {
+	if m.Rows < m.Cols {
+		panic("mathg: MatN.QR needs at least as many rows as columns")
+	}
+	rows, cols := m.Rows, m.Cols
+	a := m.Clone()
+	q := &QR{a, make([]float64, cols)}
+	for k := 0; k < cols; k++ {
+		nrm := 0.
+		for i := k; i < rows; i++ {
+			nrm = math.Hypot(nrm, a.Data[i*cols+k])
+		}
+		if nrm != 0. {
+			if a.Data[k*cols+k] < 0. {
+				nrm = -nrm
+			}
+			for i := k; i < rows; i++ {
+				a.Data[i*cols+k] /= nrm
+			}
+			a.Data[k*cols+k] += 1.
+			for j := k + 1; j < cols; j++ {
+				s := 0.
+				for i := k; i < rows; i++ {
+					s += a.Data[i*cols+k] * a.Data[i*cols+j]
+				}
+				s = -s / a.Data[k*cols+k]
+				for i := k; i < rows; i++ {
+					a.Data[i*cols+j] += s * a.Data[i*cols+k]
+				}
+			}
+		}
+		q.rdiag[k] = -nrm
+	}
+	return q
+}
+
+/*
+IsFullRank reports whether no diagonal element of R is negligible.
+*/
+func (q *QR) IsFullRank() bool {
+	max := 0.
+	for _, d := range q.rdiag {
+		max = math.Max(max, math.Abs(d))
+	}
+	for _, d := range q.rdiag {
+		if math.Abs(d) <= singularEpsilon*max || d == 0. {
+			return false
+		}
+	}
+	return true
+}
+
+func (q *QR) R() *MatN {
+	cols := q.qr.Cols
+	r := NewMatN(cols, cols)
+	for i := 0; i < cols; i++ {
+		r.Data[i*cols+i] = q.rdiag[i]
+		for j := i + 1; j < cols; j++ {
+			r.Data[i*cols+j] = q.qr.Data[i*cols+j]
+		}
+	}
+	return r
+}
+
+/*
+Q returns the rows x cols matrix with orthonormal columns.
+*/
+func (q *QR) Q() *MatN {
+	rows, cols := q.qr.Rows, q.qr.Cols
+	a := q.qr.Data
+	r := NewMatN(rows, cols)
+	for k := cols - 1; k >= 0; k-- {
+		r.Data[k*cols+k] = 1.
+		for j := k; j < cols; j++ {
+			if a[k*cols+k] == 0. {
+				continue
+			}
+			s := 0.
+			for i := k; i < rows; i++ {
+				s += a[i*cols+k] * r.Data[i*cols+j]
+			}
+			s = -s / a[k*cols+k]
+			for i := k; i < rows; i++ {
+				r.Data[i*cols+j] += s * a[i*cols+k]
+			}
+		}
+	}
+	return r
+}
+
+/*
+Solve returns the least squares solution of A * x = b. ok is false when A
+does not have full column rank.
+*/
+func (q *QR) Solve(b []float64) ([]float64, bool) {
+	rows, cols := q.qr.Rows, q.qr.Cols
+	if len(b) != rows {
+		panic("mathg: QR.Solve dimension mismatch")
+	}
+	if !q.IsFullRank() {
+		return nil, false
+	}
+	a := q.qr.Data
+	y := make([]float64, rows)
+	copy(y, b)
+	for k := 0; k < cols; k++ {
+		s := 0.
+		for i := k; i < rows; i++ {
+			s += a[i*cols+k] * y[i]
+		}
+		s = -s / a[k*cols+k]
+		for i := k; i < rows; i++ {
+			y[i] += s * a[i*cols+k]
+		}
+	}
+	x := y[:cols]
+	for k := cols - 1; k >= 0; k-- {
+		for j := k + 1; j < cols; j++ {
+			x[k] -= a[k*cols+j] * x[j]
+		}
+		x[k] /= q.rdiag[k]
+	}
+	return x, true
+}
+
+/*
+Cholesky returns the lower triangular L with A = L * L^T. ok is false when
+the matrix is not symmetric positive definite.
+*/
+func (m *MatN) Cholesky() (l *MatN, ok bool) {
+	if !m.IsSquare() {
+		panic("mathg: MatN.Cholesky of a non square matrix")
+	}
+	n := m.Rows
+	l = NewMatN(n, n)
+	ok = true
+	tol := singularEpsilon * m.maxAbs()
+	for j := 0; j < n; j++ {
+		d := 0.
+		for k := 0; k < j; k++ {
+			s := m.Data[j*n+k]
+			for i := 0; i < k; i++ {
+				s -= l.Data[k*n+i] * l.Data[j*n+i]
+			}
+			s /= l.Data[k*n+k]
+			l.Data[j*n+k] = s
+			d += s * s
+			if math.Abs(m.Data[j*n+k]-m.Data[k*n+j]) > tol {
+				ok = false
+			}
+		}
+		d = m.Data[j*n+j] - d
+		if d <= tol {
+			return l, false
+		}
+		l.Data[j*n+j] = math.Sqrt(d)
+	}
+	return l, ok
+}
+
+/*
+CholeskySolve solves A * x = b given the factor l returned by Cholesky.
+*/
+func CholeskySolve(l *MatN, b []float64) []float64 {
+	n := l.Rows
+	if len(b) != n {
+		panic("mathg: CholeskySolve dimension mismatch")
+	}
+	x := make([]float64, n)
+	copy(x, b)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			x[i] -= l.Data[i*n+j] * x[j]
+		}
+		x[i] /= l.Data[i*n+i]
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= l.Data[j*n+i] * x[j]
+		}
+		x[i] /= l.Data[i*n+i]
+	}
+	return x
+}
+
+/*
+Solve solves A * x = b with LU for square matrices, returns the least
+squares solution with QR when there are more rows than columns, and the
+minimum norm solution when there are fewer. ok is false for singular or rank
+deficient systems.
+*/
+func (m *MatN) Solve(b []float64) ([]float64, bool) {
+	if m.IsSquare() {
+		lu, _ := m.LU()
+		return lu.Solve(b)
+	}
+	if m.Rows > m.Cols {
+		return m.QR().Solve(b)
+	}
+	return m.minimumNormSolve(b)
+}
+
+// With A^T = Q * R, x = Q * R^-T * b solves the underdetermined A * x = b
+// and is orthogonal to the null space of A.
+func (m *MatN) minimumNormSolve(b []float64) ([]float64, bool) {
+	if len(b) != m.Rows {
+		panic("mathg: MatN.Solve dimension mismatch")
+	}
+	qr := m.Transpose().QR()
+	if !qr.IsFullRank() {
+		return nil, false
+	}
+	r := qr.R()
+	n := m.Rows
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		s := b[i]
+		for j := 0; j < i; j++ {
+			s -= r.Data[j*n+i] * y[j]
+		}
+		y[i] = s / r.Data[i*n+i]
+	}
+	return qr.Q().MultiplyVec(y), true
+}
+
+func (m *MatN) Determinant() float64 {
+	lu, _ := m.LU()
+	return lu.Determinant()
+}
+
+/*
+Inverse returns the inverse of a square matrix, or nil and false when it is
+singular.
+*/
+func (m *MatN) Inverse() (*MatN, bool) {
+	lu, ok := m.LU()
+	if !ok {
+		return nil, false
+	}
+	n := m.Rows
+	inv := NewMatN(n, n)
+	e := make([]float64, n)
+	for j := 0; j < n; j++ {
+		for i := range e {
+			e[i] = 0.
+		}
+		e[j] = 1.
+		x, _ := lu.Solve(e)
+		for i := 0; i < n; i++ {
+			inv.Data[i*n+j] = x[i]
+		}
+	}
+	return inv, true
+}
+
+func (m *Mat2) ToMatN() *MatN {
+	return NewMatNFromRows(m.rows())
+}
+
+func (m *Mat3) ToMatN() *MatN {
+	return NewMatNFromRows(m.rows())
+}
+
+func (m *Mat4) ToMatN() *MatN {
+	return NewMatNFromRows(m.rows())
+}
+
+func (m *MatN) block(n int) [][]float64 {
+	if m.Rows < n || m.Cols < n {
+		panic("mathg: MatN is smaller than the requested matrix")
+	}
+	r := make([][]float64, n)
+	for i := range r {
+		r[i] = m.Data[i*m.Cols : i*m.Cols+n]
+	}
+	return r
+}
+
+/*
+Mat2 returns the top left 2x2 block.
+*/
+func (m *MatN) Mat2() *Mat2 {
+	return mat2FromRows(m.block(2))
+}
+
+/*
+Mat3 returns the top left 3x3 block.
+*/
+func (m *MatN) Mat3() *Mat3 {
+	return mat3FromRows(m.block(3))
+}
+
+/*
+Mat4 returns the top left 4x4 block.
+*/
+func (m *MatN) Mat4() *Mat4 {
+	return mat4FromRows(m.block(4))
+}
+
+/*
+ToMatN returns v as a column vector.
+*/
+func (v *Vec2) ToMatN() *MatN {
+	return &MatN{2, 1, []float64{v.X, v.Y}}
+}
+
+func (v *Vec3) ToMatN() *MatN {
+	return &MatN{3, 1, []float64{v.X, v.Y, v.Z}}
+}
+
+func (v *Vec4) ToMatN() *MatN {
+	return &MatN{4, 1, []float64{v.X, v.Y, v.Z, v.W}}
+}
+
+/*
+Vec2 returns the first two elements of Data, such as a column or row vector.
+*/
+func (m *MatN) Vec2() *Vec2 {
+	return &Vec2{m.Data[0], m.Data[1]}
+}
+
+func (m *MatN) Vec3() *Vec3 {
+	return &Vec3{m.Data[0], m.Data[1], m.Data[2]}
+}
+
+func (m *MatN) Vec4() *Vec4 {
+	return &Vec4{m.Data[0], m.Data[1], m.Data[2], m.Data[3]}
+}
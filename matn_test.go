@@ -0,0 +1,110 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func slicesNearlyEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !mathg.NearlyEqual(a[i], b[i], 1e-9) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMatNSolveLU(t *testing.T) {
+	m := mathg.NewMatNFromRows([][]float64{
+		{0., 2., 1., 4., 1.},
+		{3., 1., 0., 2., 2.},
+		{1., 1., 5., 0., 1.},
+		{2., 0., 1., 1., 7.},
+		{1., 4., 2., 3., 0.},
+	})
+	want := []float64{1., -2., 0.5, 3., -1.}
+	x, ok := m.Solve(m.MultiplyVec(want))
+	if !ok || !slicesNearlyEqual(x, want) {
+		t.Fatalf("Solve = %v, want %v", x, want)
+	}
+}
+
+func TestMatNDeterminant(t *testing.T) {
+	m4 := &mathg.Mat4{1., 2., 3., 4., 0., 1., 4., 2., 5., 6., 0., 1., 1., 1., 2., 7.}
+	if !mathg.NearlyEqual(m4.ToMatN().Determinant(), m4.Determinant(), 1e-9) {
+		t.Fatalf("Determinant = %f, want %f", m4.ToMatN().Determinant(), m4.Determinant())
+	}
+	if !mat4NearlyEqual(m4.ToMatN().Mat4(), m4) {
+		t.Fatal("Mat4 round trip through MatN failed")
+	}
+	singular := mathg.NewMatNFromRows([][]float64{{1., 2.}, {2., 4.}})
+	if _, ok := singular.Solve([]float64{1., 1.}); ok {
+		t.Fatal("Solve accepted a singular system")
+	}
+}
+
+func TestMatNQRLeastSquares(t *testing.T) {
+	// Fit y = a + b * x through points on the line y = 2 + 3x.
+	a := mathg.NewMatNFromRows([][]float64{{1., 0.}, {1., 1.}, {1., 2.}, {1., 3.}})
+	x, ok := a.Solve([]float64{2., 5., 8., 11.})
+	if !ok || !slicesNearlyEqual(x, []float64{2., 3.}) {
+		t.Fatalf("Least squares = %v", x)
+	}
+	qr := a.QR()
+	p := qr.Q().Multiply(qr.R())
+	if !slicesNearlyEqual(p.Data, a.Data) {
+		t.Fatalf("Q * R = %v", p.Data)
+	}
+}
+
+func TestMatNCholesky(t *testing.T) {
+	m := mathg.NewMatNFromRows([][]float64{{4., 2., 0.4}, {2., 5., 1.}, {0.4, 1., 3.}})
+	l, ok := m.Cholesky()
+	if !ok {
+		t.Fatal("Cholesky rejected a positive definite matrix")
+	}
+	if !slicesNearlyEqual(l.Multiply(l.Transpose()).Data, m.Data) {
+		t.Fatal("L * L^T does not reproduce the matrix")
+	}
+	want := []float64{1., 2., 3.}
+	if x := mathg.CholeskySolve(l, m.MultiplyVec(want)); !slicesNearlyEqual(x, want) {
+		t.Fatalf("CholeskySolve = %v", x)
+	}
+	if _, ok := mathg.NewMatNFromRows([][]float64{{1., 2.}, {2., 1.}}).Cholesky(); ok {
+		t.Fatal("Cholesky accepted an indefinite matrix")
+	}
+}
+
+func TestMatNMinimumNorm(t *testing.T) {
+	// x + y + z = 3 is closest to the origin at (1, 1, 1).
+	a := mathg.NewMatNFromRows([][]float64{{1., 1., 1.}})
+	x, ok := a.Solve([]float64{3.})
+	if !ok || !slicesNearlyEqual(x, []float64{1., 1., 1.}) {
+		t.Fatalf("minimum norm solution = %v", x)
+	}
+	a = mathg.NewMatNFromRows([][]float64{{1., 2., 0., -1.}, {0., 1., 3., 2.}})
+	b := []float64{4., -1.}
+	x, ok = a.Solve(b)
+	if !ok || !slicesNearlyEqual(a.MultiplyVec(x), b) {
+		t.Fatalf("A * x = %v, want %v", a.MultiplyVec(x), b)
+	}
+	// x lies in the row space of A, so it has no null space component.
+	null := []float64{6., -3., 1., 0.}
+	if !slicesNearlyEqual(a.MultiplyVec(null), []float64{0., 0.}) {
+		t.Fatal("test null vector is wrong")
+	}
+	d := 0.
+	for i := range x {
+		d += x[i] * null[i]
+	}
+	if !mathg.NearlyEqual(d, 0., 1e-9) {
+		t.Fatalf("solution has null space component %f", d)
+	}
+	if _, ok := mathg.NewMatNFromRows([][]float64{{1., 2., 3.}, {2., 4., 6.}}).Solve([]float64{1., 2.}); ok {
+		t.Fatal("Solve accepted a rank deficient underdetermined system")
+	}
+}
@@ -0,0 +1,248 @@
+package mathg
+
+import "math"
+
+type Handedness int
+
+const (
+	RightHanded Handedness = iota
+	LeftHanded
+)
+
+/*
+DepthRange is the range of normalized device depth, [-1, 1] for OpenGL and
+[0, 1] for Direct3D, Vulkan and Metal.
+*/
+type DepthRange int
+
+const (
+	DepthNegativeOneToOne DepthRange = iota
+	DepthZeroToOne
+)
+
+/*
+ClipSpace describes the view and clip space conventions a projection is
+built for. A right handed view looks down -Z and a left handed one down +Z.
+ReversedZ maps the near plane to the far end of the depth range, and
+InfiniteFar pushes the far plane of perspective projections to infinity.
+The zero value is the OpenGL convention.
+*/
+type ClipSpace struct {
+	Handedness  Handedness
+	Depth       DepthRange
+	ReversedZ   bool
+	InfiniteFar bool
+}
+
+var (
+	OpenGLClipSpace   = ClipSpace{Handedness: RightHanded, Depth: DepthNegativeOneToOne}
+	Direct3DClipSpace = ClipSpace{Handedness: LeftHanded, Depth: DepthZeroToOne}
+)
+
+// Sign of view space z in front of the camera.
+func (c ClipSpace) forward() float64 {
+	if c.Handedness == LeftHanded {
+		return 1.
+	}
+	return -1.
+}
+
+// Normalized device depth of the near and far planes.
+func (c ClipSpace) depthRange() (near, far float64) {
+	near, far = -1., 1.
+	if c.Depth == DepthZeroToOne {
+		near = 0.
+	}
+	if c.ReversedZ {
+		near, far = far, near
+	}
+	return near, far
+}
+
+/*
+Frustum builds a perspective projection from the near plane rectangle
+left, right, bottom, top at distance near. far is ignored when InfiniteFar
+is set.
+*/
+func (c ClipSpace) Frustum(l, r, b, t, n, f float64) *Mat4 {
+	s := c.forward()
+	zn, zf := c.depthRange()
+	// Depth in clip space is A * d + B for a view distance d.
+	var A, B float64
+	if c.InfiniteFar {
+		A = zf
+		B = (zn - zf) * n
+	} else {
+		A = (zf*f - zn*n) / (f - n)
+		B = (zn - zf) * n * f / (f - n)
+	}
+	return &Mat4{
+		2. * n / (r - l), 0., 0., 0.,
+		0., 2. * n / (t - b), 0., 0.,
+		-s * (r + l) / (r - l), -s * (t + b) / (t - b), s * A, s,
+		0., 0., B, 0.,
+	}
+}
+
+/*
+Perspective builds a symmetric perspective projection from a vertical field
+of view in radians and a width over height aspect ratio.
+*/
+func (c ClipSpace) Perspective(fovY, aspect, near, far float64) *Mat4 {
+	t := near * math.Tan(fovY*0.5)
+	r := t * aspect
+	return c.Frustum(-r, r, -t, t, near, far)
+}
+
+/*
+Ortho builds an orthographic projection. InfiniteFar has no meaning for an
+orthographic projection and is ignored.
+*/
+func (c ClipSpace) Ortho(l, r, b, t, n, f float64) *Mat4 {
+	s := c.forward()
+	zn, zf := c.depthRange()
+	A := (zf - zn) / (f - n)
+	return &Mat4{
+		2. / (r - l), 0., 0., 0.,
+		0., 2. / (t - b), 0., 0.,
+		0., 0., s * A, 0.,
+		-(r + l) / (r - l), -(t + b) / (t - b), zn - A*n, 1.,
+	}
+}
+
+/*
+LookAt builds a view matrix for a camera at eye looking at target, with the
+view direction along -Z or +Z depending on the handedness.
+*/
+func (c ClipSpace) LookAt(eye, target, up *Vec3) *Mat4 {
+	z := target.Subtract(eye).Normalize()
+	if c.Handedness == RightHanded {
+		z = z.Negative()
+	}
+	x := up.Cross(z).Normalize()
+	y := z.Cross(x)
+	return &Mat4{
+		x.X, y.X, z.X, 0.,
+		x.Y, y.Y, z.Y, 0.,
+		x.Z, y.Z, z.Z, 0.,
+		-x.Dot(eye), -y.Dot(eye), -z.Dot(eye), 1.,
+	}
+}
+
+/*
+Frustum builds an OpenGL style perspective projection, right handed with
+[-1, 1] depth, from the near plane rectangle. It may be off-axis.
+*/
+func Frustum(l, r, b, t, n, f float64) *Mat4 {
+	return OpenGLClipSpace.Frustum(l, r, b, t, n, f)
+}
+
+/*
+OffAxis builds the combined projection and view for an eye looking through a
+physical screen rectangle, given by its lower left, lower right and upper
+left corners as seen from the eye, in the same space as eye (Kooima's
+generalized perspective projection). It is used for head tracked displays
+and CAVE walls, where the eye is rarely centered on the screen. With a
+centered eye it equals c.Frustum times c.LookAt.
+*/
+func (c ClipSpace) OffAxis(lowerLeft, lowerRight, upperLeft, eye *Vec3, near, far float64) *Mat4 {
+	vr := lowerRight.Subtract(lowerLeft).Normalize()
+	vu := upperLeft.Subtract(lowerLeft).Normalize()
+	// vn is view +Z: towards the eye when right handed, away from it when
+	// left handed.
+	vn := vr.Cross(vu).Normalize()
+	va := lowerLeft.Subtract(eye)
+	vb := lowerRight.Subtract(eye)
+	vc := upperLeft.Subtract(eye)
+	d := c.forward() * va.Dot(vn)
+	k := near / d
+	p := c.Frustum(vr.Dot(va)*k, vr.Dot(vb)*k, vu.Dot(va)*k, vu.Dot(vc)*k, near, far)
+	view := &Mat4{
+		vr.X, vu.X, vn.X, 0.,
+		vr.Y, vu.Y, vn.Y, 0.,
+		vr.Z, vu.Z, vn.Z, 0.,
+		-vr.Dot(eye), -vu.Dot(eye), -vn.Dot(eye), 1.,
+	}
+	return p.Multiply(view)
+}
+
+type StereoEye struct {
+	Projection *Mat4
+	View       *Mat4
+}
+
+/*
+StereoPair builds parallel axis, asymmetric frustum projections for two eyes
+ipd apart, converging on the plane at distance convergence. view is the view
+matrix of the point between the eyes.
+*/
+func (c ClipSpace) StereoPair(view *Mat4, fovY, aspect, near, far, ipd, convergence float64) (left, right StereoEye) {
+	t := near * math.Tan(fovY*0.5)
+	w := t * aspect
+	shift := 0.5 * ipd * near / convergence
+	left = StereoEye{
+		Projection: c.Frustum(-w+shift, w+shift, -t, t, near, far),
+		View:       (&Mat4{}).Identity().Translation(&Vec3{0.5 * ipd, 0., 0.}).Multiply(view),
+	}
+	right = StereoEye{
+		Projection: c.Frustum(-w-shift, w-shift, -t, t, near, far),
+		View:       (&Mat4{}).Identity().Translation(&Vec3{-0.5 * ipd, 0., 0.}).Multiply(view),
+	}
+	return left, right
+}
+
+/*
+Jitter offsets the projection m by dx, dy pixels on a width by height
+target, as used for temporal anti-aliasing. It works for perspective and
+orthographic projections.
+*/
+func (m *Mat4) Jitter(dx, dy, width, height float64) *Mat4 {
+	ox := 2. * dx / width
+	oy := 2. * dy / height
+	j := *m
+	j.M11 += ox * m.M41
+	j.M12 += ox * m.M42
+	j.M13 += ox * m.M43
+	j.M14 += ox * m.M44
+	j.M21 += oy * m.M41
+	j.M22 += oy * m.M42
+	j.M23 += oy * m.M43
+	j.M24 += oy * m.M44
+	return &j
+}
+
+/*
+ObliqueNearPlane replaces the near plane of the projection proj with
+clipPlane, given in view space and facing away from the camera, so that
+geometry behind it is clipped (Lengyel's method). The far plane is tilted to
+keep the whole original view volume, which costs some depth precision.
+*/
+func (c ClipSpace) ObliqueNearPlane(proj *Mat4, clipPlane *Vec4) *Mat4 {
+	zn, zf := c.depthRange()
+	// q is the corner of the original far plane opposite the clip plane.
+	corner := &Vec4{math.Copysign(1., clipPlane.X), math.Copysign(1., clipPlane.Y), zf, 1.}
+	q := corner.MultiplyMat4(proj.Inverse())
+	a := (zf - zn) / clipPlane.Dot(q)
+	m := *proj
+	m.SetRow(2, clipPlane.MultiplyScalar(a).Add(proj.Row(3).MultiplyScalar(zn)))
+	return &m
+}
+
+/*
+ObliqueNearPlane is ClipSpace.ObliqueNearPlane for the [0, 1] depth of
+Perspective.
+*/
+func (m *Mat4) ObliqueNearPlane(clipPlane *Vec4) *Mat4 {
+	return ClipSpace{Depth: DepthZeroToOne}.ObliqueNearPlane(m, clipPlane)
+}
+
+/*
+ReflectionViewMatrix returns the view of the camera mirrored in the world
+space plane {a, b, c, d}. The mirror flips triangle winding, so front faces
+must be swapped while rendering with it. With plane facing the original
+camera, the clip plane for ObliqueNearPlane is
+reflectedView.TransformPlane(plane).
+*/
+func ReflectionViewMatrix(view *Mat4, plane *Vec4) *Mat4 {
+	return view.Multiply((&Mat4{}).Reflection(plane))
+}
@@ -0,0 +1,183 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestClipSpaceDepth(t *testing.T) {
+	cases := []struct {
+		clip      mathg.ClipSpace
+		near, far float64
+	}{
+		{mathg.OpenGLClipSpace, -1., 1.},
+		{mathg.Direct3DClipSpace, 0., 1.},
+		{mathg.ClipSpace{Depth: mathg.DepthZeroToOne, ReversedZ: true}, 1., 0.},
+		{mathg.ClipSpace{Handedness: mathg.LeftHanded, ReversedZ: true}, 1., -1.},
+	}
+	for _, c := range cases {
+		s := 1.
+		if c.clip.Handedness == mathg.RightHanded {
+			s = -1.
+		}
+		for _, m := range []*mathg.Mat4{
+			c.clip.Perspective(1.2, 1.5, 0.5, 50.),
+			c.clip.Ortho(-2., 2., -1., 1., 0.5, 50.),
+		} {
+			n := m.TransformPoint(&mathg.Vec3{0., 0., s * 0.5})
+			f := m.TransformPoint(&mathg.Vec3{0., 0., s * 50.})
+			if !mathg.NearlyEqual(n.Z, c.near, tolerance) || !mathg.NearlyEqual(f.Z, c.far, tolerance) {
+				t.Fatalf("%+v maps near/far to %f, %f", c.clip, n.Z, f.Z)
+			}
+		}
+	}
+	// Perspective uses [0, 1] depth; z' = 2z - w takes it to [-1, 1].
+	remap := &mathg.Mat4{1., 0., 0., 0., 0., 1., 0., 0., 0., 0., 2., 0., 0., 0., -1., 1.}
+	if m := mathg.OpenGLClipSpace.Perspective(1.2, 1.5, 0.5, 50.); !mat4NearlyEqual(m, remap.Multiply(mathg.Perspective(1.2, 1.5, 0.5, 50.))) {
+		t.Fatal("OpenGL perspective disagrees with Perspective remapped to [-1, 1]")
+	}
+}
+
+func TestClipSpaceInfiniteFar(t *testing.T) {
+	clip := mathg.ClipSpace{Depth: mathg.DepthZeroToOne, ReversedZ: true, InfiniteFar: true}
+	m := clip.Perspective(math.Pi/2., 1., 0.1, 0.)
+	if p := m.TransformPoint(&mathg.Vec3{0., 0., -0.1}); !mathg.NearlyEqual(p.Z, 1., tolerance) {
+		t.Fatalf("near depth = %f", p.Z)
+	}
+	if p := m.TransformPoint(&mathg.Vec3{0., 0., -1e9}); !mathg.NearlyEqual(p.Z, 0., 1e-6) {
+		t.Fatalf("far depth = %f", p.Z)
+	}
+	if p := m.TransformPoint(&mathg.Vec3{1., 1., -1.}); !vec3NearlyEqual(&mathg.Vec3{p.X, p.Y, 0.}, &mathg.Vec3{1., 1., 0.}) {
+		t.Fatalf("90 degree frustum corner = %v", p)
+	}
+}
+
+func TestClipSpaceLookAt(t *testing.T) {
+	eye, target, up := &mathg.Vec3{1., 2., 3.}, &mathg.Vec3{4., 2., -1.}, &mathg.Vec3{0., 1., 0.}
+	if !mat4NearlyEqual(mathg.OpenGLClipSpace.LookAt(eye, target, up), eye.LookAt(target, up)) {
+		t.Fatal("right handed LookAt disagrees with Vec3.LookAt")
+	}
+	lh := mathg.Direct3DClipSpace.LookAt(eye, target, up)
+	if p := lh.TransformPoint(target); !vec3NearlyEqual(p, &mathg.Vec3{0., 0., 5.}) {
+		t.Fatalf("left handed view of target = %v", p)
+	}
+	if !mathg.NearlyEqual(lh.Determinant(), 1., tolerance) {
+		t.Fatal("left handed view should be a rotation")
+	}
+}
+
+func TestOffAxis(t *testing.T) {
+	// A 4x2 screen one unit in front of a centered eye is a plain frustum.
+	clip := mathg.OpenGLClipSpace
+	m := clip.OffAxis(&mathg.Vec3{-2., -1., -1.}, &mathg.Vec3{2., -1., -1.}, &mathg.Vec3{-2., 1., -1.}, &mathg.Vec3{}, 0.5, 20.)
+	if !mat4NearlyEqual(m, mathg.Frustum(-1., 1., -0.5, 0.5, 0.5, 20.)) {
+		t.Fatal("centered OffAxis should match Frustum")
+	}
+	// The screen corners stay on the NDC corners wherever the eye moves.
+	eye := &mathg.Vec3{0.7, -0.3, 1.5}
+	m = clip.OffAxis(&mathg.Vec3{-2., -1., -1.}, &mathg.Vec3{2., -1., -1.}, &mathg.Vec3{-2., 1., -1.}, eye, 0.5, 20.)
+	if p := m.TransformPoint(&mathg.Vec3{2., 1., -1.}); !mathg.NearlyEqual(p.X, 1., tolerance) || !mathg.NearlyEqual(p.Y, 1., tolerance) {
+		t.Fatalf("upper right corner = %v", p)
+	}
+	// Looking down -Z in a left handed world, +X is to the left.
+	d3d := mathg.Direct3DClipSpace
+	ll, lr, ul := &mathg.Vec3{2., -1., -1.}, &mathg.Vec3{-2., -1., -1.}, &mathg.Vec3{2., 1., -1.}
+	lh := d3d.OffAxis(ll, lr, ul, &mathg.Vec3{}, 0.5, 20.)
+	want := d3d.Frustum(-1., 1., -0.5, 0.5, 0.5, 20.).Multiply(d3d.LookAt(&mathg.Vec3{}, &mathg.Vec3{0., 0., -1.}, &mathg.Vec3{0., 1., 0.}))
+	if !mat4NearlyEqual(lh, want) {
+		t.Fatal("centered left handed OffAxis should match Frustum * LookAt")
+	}
+	lh = d3d.OffAxis(ll, lr, ul, eye, 0.5, 20.)
+	if p := lh.TransformPoint(ll); !mathg.NearlyEqual(p.X, -1., tolerance) || !mathg.NearlyEqual(p.Y, -1., tolerance) || p.Z < 0. || p.Z > 1. {
+		t.Fatalf("left handed lower left corner = %v", p)
+	}
+}
+
+func TestStereoPair(t *testing.T) {
+	view := (&mathg.Vec3{0., 1., 5.}).LookAt(&mathg.Vec3{0., 1., 0.}, &mathg.Vec3{0., 1., 0.})
+	left, right := mathg.OpenGLClipSpace.StereoPair(view, 1., 1.5, 0.1, 100., 0.064, 2.)
+	// Points on the convergence plane have zero parallax.
+	p := &mathg.Vec3{0.3, 1.2, 3.}
+	l := left.Projection.Multiply(left.View).TransformPoint(p)
+	r := right.Projection.Multiply(right.View).TransformPoint(p)
+	if !vec3NearlyEqual(l, r) {
+		t.Fatalf("parallax at convergence: %v, %v", l, r)
+	}
+	far := &mathg.Vec3{0., 1., -10.}
+	if left.Projection.Multiply(left.View).TransformPoint(far).X >= right.Projection.Multiply(right.View).TransformPoint(far).X {
+		t.Fatal("points behind the convergence plane should have positive parallax")
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for _, m := range []*mathg.Mat4{
+		mathg.OpenGLClipSpace.Perspective(1., 1.5, 0.1, 100.),
+		mathg.OpenGLClipSpace.Ortho(-3., 3., -2., 2., 0.1, 100.),
+	} {
+		p := &mathg.Vec3{0.4, -0.2, -3.}
+		a := m.TransformPoint(p)
+		b := m.Jitter(0.5, -0.25, 1920., 1080.).TransformPoint(p)
+		if !vec3NearlyEqual(b.Subtract(a), &mathg.Vec3{1. / 1920., -0.5 / 1080., 0.}) {
+			t.Fatalf("jitter offset = %v", b.Subtract(a))
+		}
+	}
+}
+
+func TestObliqueNearPlane(t *testing.T) {
+	// A tilted view space plane through (0, 0, -3), with the camera behind it.
+	plane := &mathg.Vec4{0., 0.6, -0.8, -2.4}
+	onPlane := []*mathg.Vec3{{0.2, 0., -3.}, {-0.3, 0.4, -2.7}, {0.5, -0.4, -3.3}}
+	for _, clip := range []mathg.ClipSpace{
+		mathg.OpenGLClipSpace,
+		{Depth: mathg.DepthZeroToOne},
+		{Depth: mathg.DepthZeroToOne, ReversedZ: true},
+	} {
+		m := clip.ObliqueNearPlane(clip.Perspective(1.2, 1.5, 0.5, 50.), plane)
+		near := -1.
+		if clip.Depth == mathg.DepthZeroToOne {
+			near = 0.
+		}
+		if clip.ReversedZ {
+			near = 1.
+		}
+		for _, p := range onPlane {
+			if z := m.TransformPoint(p).Z; !mathg.NearlyEqual(z, near, 1e-9) {
+				t.Fatalf("%+v depth on the clip plane = %f, want %f", clip, z, near)
+			}
+		}
+	}
+	p := mathg.Perspective(1.2, 1.5, 0.5, 50.)
+	if !mat4NearlyEqual(p.ObliqueNearPlane(plane), mathg.ClipSpace{Depth: mathg.DepthZeroToOne}.ObliqueNearPlane(p, plane)) {
+		t.Fatal("Mat4.ObliqueNearPlane should use [0, 1] depth")
+	}
+}
+
+func TestReflectionViewMatrix(t *testing.T) {
+	eye := &mathg.Vec3{1., 3., 4.}
+	view := eye.LookAt(&mathg.Vec3{0., 0., -2.}, &mathg.Vec3{0., 1., 0.})
+	water := &mathg.Vec4{0., 1., 0., -1.}
+	r := mathg.ReflectionViewMatrix(view, water)
+	if e := r.Inverse().TransformPoint(&mathg.Vec3{}); !vec3NearlyEqual(e, &mathg.Vec3{1., -1., 4.}) {
+		t.Fatalf("reflected eye = %v", e)
+	}
+	// The water plane faces the real camera, so in the reflected view the
+	// camera sits behind it, as ObliqueNearPlane expects.
+	if c := r.TransformPlane(water); c.W >= 0. {
+		t.Fatalf("reflected clip plane = %v", c)
+	}
+}
+
+func TestLegacyProjectionConventions(t *testing.T) {
+	if !mat4NearlyEqual(mathg.PerspectiveFOV(1.2, 1920., 1080., 0.5, 50.), mathg.Direct3DClipSpace.Perspective(1.2, 1920./1080., 0.5, 50.)) {
+		t.Fatal("PerspectiveFOV should be left handed with [0, 1] depth")
+	}
+	infinite := mathg.ClipSpace{InfiniteFar: true}
+	if !mat4NearlyEqual(mathg.PerspectiveInfinite(1.2, 1.5, 0.5), infinite.Perspective(1.2, 1.5, 0.5, 0.)) {
+		t.Fatal("PerspectiveInfinite should be right handed with [-1, 1] depth")
+	}
+	if !mat4NearlyEqual(mathg.Ortho(-2., 3., -1., 4., 0.5, 50.), mathg.OpenGLClipSpace.Ortho(-2., 3., -1., 4., 0.5, 50.)) {
+		t.Fatal("Ortho should be right handed with [-1, 1] depth")
+	}
+}
@@ -81,7 +81,7 @@ func (q *Quaternion) Normalize() *Quaternion {
 }
 
 func (q *Quaternion) Dot(q1 *Quaternion) float64 {
-	return q.X*q1.X + q.Y*q1.Y + q.Z*q1.Z + q.W + q1.W
+	return q.X*q1.X + q.Y*q1.Y + q.Z*q1.Z + q.W*q1.W
 }
 
 func (q *Quaternion) Power(exponent float64) *Quaternion {
@@ -95,6 +95,30 @@ func (q *Quaternion) Power(exponent float64) *Quaternion {
 	}
 }
 
+func (q *Quaternion) Exp() *Quaternion {
+	l := math.Sqrt(q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+	e := math.Exp(q.W)
+	if l < epsilon {
+		return &Quaternion{q.X * e, q.Y * e, q.Z * e, math.Cos(l) * e}
+	}
+	s := math.Sin(l) * e / l
+	return &Quaternion{q.X * s, q.Y * s, q.Z * s, math.Cos(l) * e}
+}
+
+func (q *Quaternion) Log() *Quaternion {
+	l := math.Sqrt(q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+	m := q.Magnitude()
+	if l < epsilon && q.W >= 0. {
+		return &Quaternion{q.X / m, q.Y / m, q.Z / m, math.Log(m)}
+	}
+	if l == 0. {
+		// A negative real quaternion is a half turn about any axis.
+		return &Quaternion{math.Pi, 0., 0., math.Log(m)}
+	}
+	s := math.Atan2(l, q.W) / l
+	return &Quaternion{q.X * s, q.Y * s, q.Z * s, math.Log(m)}
+}
+
 func (v *Vec4) ToQuaternionFromAxisAngle(angle float64) *Quaternion {
 	half := angle * 0.5
 	s := math.Sin(half)
@@ -110,45 +134,11 @@ func (v *Vec3) ToQuaternion(v1 *Vec3) *Quaternion {
 	return q.Normalize()
 }
 
+/*
+ToQuaternion reads the rotation in the upper 3x3, which must be orthonormal.
+*/
 func (m *Mat4) ToQuaternion() *Quaternion {
-	scale := m.M11 + m.M22 + m.M33
-	if scale > 0. {
-		sqrt := math.Sqrt(scale + 1.)
-		half := 0.5 / sqrt
-		return &Quaternion{
-			(m.M23 - m.M32) * half,
-			(m.M31 - m.M13) * half,
-			(m.M12 - m.M21) * half,
-			sqrt * 0.5,
-		}
-	} else if (m.M11 >= m.M22) && (m.M11 >= m.M33) {
-		sqrt := math.Sqrt(1. + m.M11 - m.M22 - m.M33)
-		half := 0.5 / sqrt
-		return &Quaternion{
-			0.5 * sqrt,
-			(m.M12 + m.M21) * half,
-			(m.M13 + m.M31) * half,
-			(m.M23 - m.M32) * half,
-		}
-	} else if m.M22 > m.M33 {
-		sqrt := math.Sqrt(1. + m.M22 - m.M11 - m.M33)
-		half := 0.5 / sqrt
-		return &Quaternion{
-			(m.M21 + m.M12) * half,
-			0.5 * sqrt,
-			(m.M32 + m.M23) * half,
-			(m.M31 - m.M13) * half,
-		}
-	} else {
-		sqrt := math.Sqrt(1. + m.M33 - m.M11 - m.M22)
-		half := 0.5 / sqrt
-		return &Quaternion{
-			(m.M31 + m.M13) * half,
-			(m.M32 + m.M23) * half,
-			(0.5 * sqrt),
-			(m.M12 - m.M21) * half,
-		}
-	}
+	return QuaternionFromMat3(m.Mat3())
 }
 
 func (q *Quaternion) Lerp(q1 *Quaternion, percent float64) *Quaternion {
@@ -189,3 +179,145 @@ func (q *Quaternion) Angle(q1 *Quaternion) float64 {
 	s := 1. / math.Sqrt(q.LengthSquared()*q1.LengthSquared())
 	return math.Acos(q.Dot(q1) * s)
 }
+
+/*
+Derivative returns dq/dt for the world space angular velocity omega, in
+radians per second.
+*/
+func (q *Quaternion) Derivative(omega *Vec3) *Quaternion {
+	w := &Quaternion{omega.X, omega.Y, omega.Z, 0.}
+	return w.Multiply(q).MultiplyScalar(0.5)
+}
+
+/*
+Integrate rotates q by the world space angular velocity omega over dt
+seconds using the exponential map, which is exact for constant omega.
+*/
+func (q *Quaternion) Integrate(omega *Vec3, dt float64) *Quaternion {
+	h := 0.5 * dt
+	dq := &Quaternion{omega.X * h, omega.Y * h, omega.Z * h, 0.}
+	return dq.Exp().Multiply(q).Normalize()
+}
+
+/*
+IntegrateFirstOrder is the cheaper explicit Euler step q + dq/dt * dt,
+renormalized.
+*/
+func (q *Quaternion) IntegrateFirstOrder(omega *Vec3, dt float64) *Quaternion {
+	d := q.Derivative(omega)
+	r := &Quaternion{q.X + d.X*dt, q.Y + d.Y*dt, q.Z + d.Z*dt, q.W + d.W*dt}
+	return r.Normalize()
+}
+
+/*
+AngularVelocity returns the constant world space angular velocity that turns
+q0 into q1 over dt seconds, taking the shortest path.
+*/
+func AngularVelocity(q0, q1 *Quaternion, dt float64) *Vec3 {
+	dq := q1.Multiply(q0.Conjugate())
+	if dq.W < 0. {
+		dq = dq.Negative()
+	}
+	l := dq.Normalize().Log()
+	s := 2. / dt
+	return &Vec3{l.X * s, l.Y * s, l.Z * s}
+}
+
+func (q *Quaternion) RotateVec3(v *Vec3) *Vec3 {
+	u := &Vec3{q.X, q.Y, q.Z}
+	t := u.Cross(v).MultiplyScalar(2.)
+	return v.Add(t.MultiplyScalar(q.W)).Add(u.Cross(t))
+}
+
+/*
+Axis returns the unit rotation axis of q. The identity rotation has no axis
+and returns the X axis.
+*/
+func (q *Quaternion) Axis() *Vec3 {
+	v := &Vec3{q.X, q.Y, q.Z}
+	l := v.Magnitude()
+	if l < epsilon {
+		return &Vec3{1., 0., 0.}
+	}
+	if q.W < 0. {
+		l = -l
+	}
+	return v.DivideScalar(l)
+}
+
+/*
+RotationAngle returns the rotation of q about Axis in radians, in [0, pi].
+Angle is the angle between two quaternions instead.
+*/
+func (q *Quaternion) RotationAngle() float64 {
+	l := math.Sqrt(q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+	return 2. * math.Atan2(l, math.Abs(q.W))
+}
+
+func (q *Quaternion) ToAxisAngle() (*Vec3, float64) {
+	return q.Axis(), q.RotationAngle()
+}
+
+/*
+QuaternionFromMat3 expects an orthonormal rotation matrix.
+*/
+func QuaternionFromMat3(m *Mat3) *Quaternion {
+	scale := m.M11 + m.M22 + m.M33
+	if scale > 0. {
+		sqrt := math.Sqrt(scale + 1.)
+		half := 0.5 / sqrt
+		return &Quaternion{
+			(m.M32 - m.M23) * half,
+			(m.M13 - m.M31) * half,
+			(m.M21 - m.M12) * half,
+			sqrt * 0.5,
+		}
+	} else if (m.M11 >= m.M22) && (m.M11 >= m.M33) {
+		sqrt := math.Sqrt(1. + m.M11 - m.M22 - m.M33)
+		half := 0.5 / sqrt
+		return &Quaternion{
+			0.5 * sqrt,
+			(m.M12 + m.M21) * half,
+			(m.M13 + m.M31) * half,
+			(m.M32 - m.M23) * half,
+		}
+	} else if m.M22 > m.M33 {
+		sqrt := math.Sqrt(1. + m.M22 - m.M11 - m.M33)
+		half := 0.5 / sqrt
+		return &Quaternion{
+			(m.M21 + m.M12) * half,
+			0.5 * sqrt,
+			(m.M32 + m.M23) * half,
+			(m.M13 - m.M31) * half,
+		}
+	} else {
+		sqrt := math.Sqrt(1. + m.M33 - m.M11 - m.M22)
+		half := 0.5 / sqrt
+		return &Quaternion{
+			(m.M31 + m.M13) * half,
+			(m.M32 + m.M23) * half,
+			(0.5 * sqrt),
+			(m.M21 - m.M12) * half,
+		}
+	}
+}
+
+/*
+LookRotation returns the orientation that points -Z along forward with +Y
+as close to up as possible, matching the camera orientation of Vec3.LookAt.
+If forward and up are parallel the shortest rotation from -Z is used.
+*/
+func LookRotation(forward, up *Vec3) *Quaternion {
+	f := forward.Normalize()
+	side := f.Cross(up)
+	if side.LengthSquared() < epsilon {
+		return (&Rotation{}).FromTo(&Vec3{0., 0., -1.}, f).Quaternion()
+	}
+	side = side.Normalize()
+	newUp := side.Cross(f)
+	return QuaternionFromMat3(&Mat3{
+		side.X, side.Y, side.Z,
+		newUp.X, newUp.Y, newUp.Z,
+		-f.X, -f.Y, -f.Z,
+	}).Normalize()
+}
@@ -0,0 +1,136 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestQuaternionRotationMatrix(t *testing.T) {
+	// Quarter turns about X and Z take Y to Z and X to Y.
+	cases := []struct{ axis, v, want mathg.Vec3 }{
+		{mathg.Vec3{1., 0., 0.}, mathg.Vec3{0., 1., 0.}, mathg.Vec3{0., 0., 1.}},
+		{mathg.Vec3{0., 0., 1.}, mathg.Vec3{1., 0., 0.}, mathg.Vec3{0., 1., 0.}},
+	}
+	near := func(a, b float64) bool { return mathg.NearlyEqual(a, b, 1e-12) }
+	for _, c := range cases {
+		q := c.axis.ToVec4().ToQuaternionFromAxisAngle(math.Pi / 2.)
+		r := c.v.MultiplyMat3(q.RotationMatrix())
+		if !near(r.X, c.want.X) || !near(r.Y, c.want.Y) || !near(r.Z, c.want.Z) {
+			t.Fatalf("RotationMatrix maps %v to %v, want %v", c.v, *r, c.want)
+		}
+		r4 := c.v.ToVec4().MultiplyMat4(q.RotationMatrix4())
+		if !near(r4.X, c.want.X) || !near(r4.Y, c.want.Y) || !near(r4.Z, c.want.Z) {
+			t.Fatalf("RotationMatrix4 maps %v to %v, want %v", c.v, *r4, c.want)
+		}
+	}
+}
+
+func TestQuaternionDot(t *testing.T) {
+	q := &mathg.Quaternion{1., 2., 3., 4.}
+	if d := q.Dot(&mathg.Quaternion{5., 6., 7., 8.}); d != 70. {
+		t.Fatalf("Dot = %f, want 70", d)
+	}
+	// Slerp takes its angle from Dot.
+	z := &mathg.Vec4{0., 0., 1., 0.}
+	half := z.ToQuaternionFromAxisAngle(0.).Slerp(z.ToQuaternionFromAxisAngle(math.Pi/2.), 0.5)
+	want := z.ToQuaternionFromAxisAngle(math.Pi / 4.)
+	if !mathg.NearlyEqual(half.Dot(want), 1., 1e-12) {
+		t.Fatalf("Slerp halfway = %v, want %v", *half, *want)
+	}
+}
+
+func TestMat4ToQuaternion(t *testing.T) {
+	// One axis per branch: a small turn, then near half turns about X, Y and Z.
+	axes := []*mathg.Vec4{{0.3, -0.5, 0.8, 0.}, {1., 0.2, -0.1, 0.}, {0.1, 1., 0.3, 0.}, {-0.2, 0.1, 1., 0.}}
+	angles := []float64{0.7, 3., 3., 3.}
+	for i, axis := range axes {
+		q := axis.Normalize().ToQuaternionFromAxisAngle(angles[i])
+		r := q.RotationMatrix4().ToQuaternion()
+		if r.Dot(q) < 0. {
+			r = r.Negative()
+		}
+		if !quaternionNearlyEqual(r, q) {
+			t.Fatalf("ToQuaternion = %v, want %v", *r, *q)
+		}
+	}
+}
+
+func TestQuaternionIntegrate(t *testing.T) {
+	q := (&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.3, 0.8, -0.5}, mathg.EulerXYZ)
+	omega := &mathg.Vec3{0.4, -1.2, 2.}
+	r := q.Integrate(omega, 0.3)
+	if w := mathg.AngularVelocity(q, r, 0.3); !vec3NearlyEqual(w, omega) {
+		t.Fatalf("AngularVelocity = %v, want %v", *w, *omega)
+	}
+	// Constant velocity integrates exactly, whatever the step count.
+	s := q
+	for i := 0; i < 10; i++ {
+		s = s.Integrate(omega, 0.03)
+	}
+	if !quaternionNearlyEqual(s, r) {
+		t.Fatalf("ten steps = %v, one step = %v", *s, *r)
+	}
+}
+
+func TestQuaternionIntegrateFirstOrder(t *testing.T) {
+	q := (&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.3, 0.8, -0.5}, mathg.EulerXYZ)
+	omega := &mathg.Vec3{0.4, -1.2, 2.}
+	dt := 1e-4
+	exact := q.Integrate(omega, dt)
+	approx := q.IntegrateFirstOrder(omega, dt)
+	if math.Abs(approx.Dot(exact)) < 1.-1e-12 || !mathg.NearlyEqual(approx.Magnitude(), 1., tolerance) {
+		t.Fatalf("first order step = %v, exact = %v", *approx, *exact)
+	}
+	d := q.Derivative(omega)
+	fd := &mathg.Quaternion{(exact.X - q.X) / dt, (exact.Y - q.Y) / dt, (exact.Z - q.Z) / dt, (exact.W - q.W) / dt}
+	if math.Abs(fd.X-d.X)+math.Abs(fd.Y-d.Y)+math.Abs(fd.Z-d.Z)+math.Abs(fd.W-d.W) > 1e-3 {
+		t.Fatalf("Derivative = %v, finite difference = %v", *d, *fd)
+	}
+}
+
+func TestQuaternionRotateVec3(t *testing.T) {
+	q := (&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.7, -0.2, 1.9}, mathg.EulerYXZ)
+	v := &mathg.Vec3{1., -2., 0.5}
+	got := q.RotateVec3(v)
+	want := v.MultiplyMat3(q.RotationMatrix())
+	if !vec3NearlyEqual(got, want) {
+		t.Fatalf("RotateVec3 = %v, want %v", *got, *want)
+	}
+}
+
+func TestQuaternionToAxisAngle(t *testing.T) {
+	axis := &mathg.Vec3{0., 0.6, 0.8}
+	q := axis.ToVec4().ToQuaternionFromAxisAngle(2.5)
+	a, angle := q.ToAxisAngle()
+	if !vec3NearlyEqual(a, axis) || !mathg.NearlyEqual(angle, 2.5, tolerance) {
+		t.Fatalf("ToAxisAngle = %v, %f", *a, angle)
+	}
+}
+
+func TestQuaternionFromMat3(t *testing.T) {
+	for _, a := range []*mathg.Vec3{{0.1, 0.2, 0.3}, {math.Pi, 0., 0.}, {0., 3., 0.}, {0.2, 0.1, 3.}} {
+		q := (&mathg.Quaternion{}).FromEuler(a, mathg.EulerXYZ)
+		r := mathg.QuaternionFromMat3(q.RotationMatrix())
+		if r.Dot(q) < 0. {
+			r = r.Negative()
+		}
+		if !quaternionNearlyEqual(q, r) {
+			t.Fatalf("QuaternionFromMat3 = %v, want %v", *r, *q)
+		}
+	}
+}
+
+func TestLookRotation(t *testing.T) {
+	forward := &mathg.Vec3{1., 0., 1.}
+	q := mathg.LookRotation(forward, &mathg.Vec3{0., 1., 0.})
+	f := q.RotateVec3(&mathg.Vec3{0., 0., -1.})
+	if !vec3NearlyEqual(f, forward.Normalize()) {
+		t.Fatalf("LookRotation forward = %v", *f)
+	}
+	up := q.RotateVec3(&mathg.Vec3{0., 1., 0.})
+	if !vec3NearlyEqual(up, &mathg.Vec3{0., 1., 0.}) {
+		t.Fatalf("LookRotation up = %v", *up)
+	}
+}
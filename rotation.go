@@ -0,0 +1,104 @@
+package mathg
+
+import "math"
+
+/*
+Rotation wraps a unit quaternion. Every constructor and operation returns a
+normalized value, so a Rotation can be passed anywhere a rotation quaternion
+is expected. The zero value is the identity rotation.
+*/
+type Rotation struct {
+	q Quaternion
+}
+
+func newRotation(q *Quaternion) *Rotation {
+	l := q.Magnitude()
+	if l < epsilon || math.IsNaN(l) || math.IsInf(l, 0) {
+		return &Rotation{}
+	}
+	if q.W < 0. {
+		l = -l
+	}
+	return &Rotation{*q.DivideScalar(l)}
+}
+
+func (r *Rotation) Quaternion() *Quaternion {
+	if r.q.IsZero() {
+		return &Quaternion{0., 0., 0., 1.}
+	}
+	q := r.q
+	return &q
+}
+
+func (r *Rotation) Identity() *Rotation {
+	return &Rotation{}
+}
+
+func (r *Rotation) FromQuaternion(q *Quaternion) *Rotation {
+	return newRotation(q)
+}
+
+func (r *Rotation) FromAxisAngle(axis *Vec3, angle float64) *Rotation {
+	if axis.LengthSquared() < epsilon {
+		return &Rotation{}
+	}
+	return newRotation(axis.Normalize().ToVec4().ToQuaternionFromAxisAngle(angle))
+}
+
+/*
+FromTo returns the shortest rotation that turns the direction from onto the
+direction to. Opposite directions turn by 180 degrees about an arbitrary
+perpendicular axis.
+*/
+func (r *Rotation) FromTo(from, to *Vec3) *Rotation {
+	f := from.Normalize()
+	t := to.Normalize()
+	d := f.Dot(t)
+	if d < -1.+1e-12 {
+		axis := (&Vec3{1., 0., 0.}).Cross(f)
+		if axis.LengthSquared() < 1e-12 {
+			axis = (&Vec3{0., 1., 0.}).Cross(f)
+		}
+		return r.FromAxisAngle(axis, math.Pi)
+	}
+	c := f.Cross(t)
+	return newRotation(&Quaternion{c.X, c.Y, c.Z, 1. + d})
+}
+
+/*
+FromMat3 expects an orthonormal rotation matrix.
+*/
+func (r *Rotation) FromMat3(m *Mat3) *Rotation {
+	return newRotation(QuaternionFromMat3(m))
+}
+
+func (r *Rotation) LookRotation(forward, up *Vec3) *Rotation {
+	return newRotation(LookRotation(forward, up))
+}
+
+func (r *Rotation) RotateVec3(v *Vec3) *Vec3 {
+	return r.Quaternion().RotateVec3(v)
+}
+
+func (r *Rotation) Inverse() *Rotation {
+	return &Rotation{*r.Quaternion().Conjugate()}
+}
+
+/*
+Compose returns the rotation that applies r1 first and then r.
+*/
+func (r *Rotation) Compose(r1 *Rotation) *Rotation {
+	return newRotation(r.Quaternion().Multiply(r1.Quaternion()))
+}
+
+func (r *Rotation) Slerp(r1 *Rotation, percent float64) *Rotation {
+	return newRotation(r.Quaternion().Slerp(r1.Quaternion(), percent))
+}
+
+func (r *Rotation) ToMat3() *Mat3 {
+	return r.Quaternion().RotationMatrix()
+}
+
+func (r *Rotation) ToMat4() *Mat4 {
+	return r.Quaternion().RotationMatrix4()
+}
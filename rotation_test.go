@@ -0,0 +1,60 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestRotationZeroValue(t *testing.T) {
+	var r mathg.Rotation
+	v := &mathg.Vec3{1., -2., 3.}
+	if !vec3NearlyEqual(r.RotateVec3(v), v) || !mat3NearlyEqual(r.ToMat3(), (&mathg.Mat3{}).Identity()) {
+		t.Fatal("zero Rotation is not the identity")
+	}
+	if !quaternionNearlyEqual(r.Quaternion(), &mathg.Quaternion{0., 0., 0., 1.}) {
+		t.Fatalf("zero Rotation quaternion = %v", *r.Quaternion())
+	}
+	s := (&mathg.Rotation{}).FromAxisAngle(&mathg.Vec3{0., 1., 0.}, 0.7)
+	if !quaternionNearlyEqual(r.Compose(s).Quaternion(), s.Quaternion()) {
+		t.Fatal("composing with the zero Rotation changed the rotation")
+	}
+}
+
+func TestRotationFromTo(t *testing.T) {
+	from := &mathg.Vec3{1., 2., -0.5}
+	for _, to := range []*mathg.Vec3{{-0.3, 0.4, 2.}, from.Negative(), {-1., 0., 0.}} {
+		r := (&mathg.Rotation{}).FromTo(from, to)
+		if got := r.RotateVec3(from.Normalize()); !vec3NearlyEqual(got, to.Normalize()) {
+			t.Fatalf("FromTo(%v, %v) maps to %v", *from, *to, *got)
+		}
+	}
+	// Opposite X vectors need the fallback axis.
+	r := (&mathg.Rotation{}).FromTo(&mathg.Vec3{1., 0., 0.}, &mathg.Vec3{-1., 0., 0.})
+	if !vec3NearlyEqual(r.RotateVec3(&mathg.Vec3{1., 0., 0.}), &mathg.Vec3{-1., 0., 0.}) {
+		t.Fatal("FromTo failed for opposite X vectors")
+	}
+}
+
+func TestRotationComposeInverse(t *testing.T) {
+	step := (&mathg.Rotation{}).FromAxisAngle(&mathg.Vec3{0.2, 1., -0.4}, 0.001)
+	r := &mathg.Rotation{}
+	for i := 0; i < 10000; i++ {
+		r = r.Compose(step)
+	}
+	if !mathg.NearlyEqual(r.Quaternion().Magnitude(), 1., 1e-12) {
+		t.Fatalf("Compose drifted to magnitude %.15f", r.Quaternion().Magnitude())
+	}
+	want := (&mathg.Rotation{}).FromAxisAngle(&mathg.Vec3{0.2, 1., -0.4}, 10.-2.*math.Pi)
+	if !quaternionNearlyEqual(r.Quaternion(), want.Quaternion()) {
+		t.Fatalf("10000 steps = %v, want %v", *r.Quaternion(), *want.Quaternion())
+	}
+	v := &mathg.Vec3{0.5, -1., 2.}
+	if !vec3NearlyEqual(r.Inverse().RotateVec3(r.RotateVec3(v)), v) {
+		t.Fatal("Inverse does not undo the rotation")
+	}
+	if !quaternionNearlyEqual(r.Compose(r.Inverse()).Quaternion(), &mathg.Quaternion{0., 0., 0., 1.}) {
+		t.Fatal("r * r^-1 is not the identity")
+	}
+}
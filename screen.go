@@ -0,0 +1,95 @@
+package mathg
+
+/*
+Screen coordinates are in pixels with the origin at the bottom left corner,
+as in OpenGL, and depth in [0, 1]. A viewport is stored as
+{x, y, width, height}. Flip y as height - y for window systems that put the
+origin at the top left.
+*/
+
+/*
+Viewport builds the matrix taking normalized device coordinates to screen
+coordinates.
+*/
+func (c ClipSpace) Viewport(viewport Vec4) *Mat4 {
+	hw, hh := 0.5*viewport.Z, 0.5*viewport.W
+	zs, zo := 0.5, 0.5
+	if c.Depth == DepthZeroToOne {
+		zs, zo = 1., 0.
+	}
+	return &Mat4{
+		hw, 0., 0., 0.,
+		0., hh, 0., 0.,
+		0., 0., zs, 0.,
+		viewport.X + hw, viewport.Y + hh, zo, 1.,
+	}
+}
+
+func (c ClipSpace) NDCToScreen(ndc *Vec3, viewport Vec4) *Vec3 {
+	z := ndc.Z
+	if c.Depth == DepthNegativeOneToOne {
+		z = 0.5 * (z + 1.)
+	}
+	return &Vec3{
+		viewport.X + 0.5*(ndc.X+1.)*viewport.Z,
+		viewport.Y + 0.5*(ndc.Y+1.)*viewport.W,
+		z,
+	}
+}
+
+func (c ClipSpace) ScreenToNDC(screen *Vec3, viewport Vec4) *Vec3 {
+	z := screen.Z
+	if c.Depth == DepthNegativeOneToOne {
+		z = 2.*z - 1.
+	}
+	return &Vec3{
+		2.*(screen.X-viewport.X)/viewport.Z - 1.,
+		2.*(screen.Y-viewport.Y)/viewport.W - 1.,
+		z,
+	}
+}
+
+func (c ClipSpace) Project(world *Vec3, view, proj *Mat4, viewport Vec4) *Vec3 {
+	return c.NDCToScreen(proj.Multiply(view).TransformPoint(world), viewport)
+}
+
+/*
+Unproject maps a screen point, including its depth, back to world space. The
+view-projection must be invertible.
+*/
+func (c ClipSpace) Unproject(screen *Vec3, view, proj *Mat4, viewport Vec4) *Vec3 {
+	return proj.Multiply(view).Inverse().TransformPoint(c.ScreenToNDC(screen, viewport))
+}
+
+/*
+ScreenPointToRay returns the world space ray through a pixel, starting on the
+near plane, with a unit direction. It works for perspective and orthographic
+projections, reversed depth and an infinite far plane.
+*/
+func (c ClipSpace) ScreenPointToRay(screen *Vec2, view, proj *Mat4, viewport Vec4) (origin, direction *Vec3) {
+	inv := proj.Multiply(view).Inverse()
+	near := 0.
+	if c.ReversedZ {
+		near = 1.
+	}
+	// Depth 0.5 stays finite when the far plane is at infinity.
+	origin = inv.TransformPoint(c.ScreenToNDC(&Vec3{screen.X, screen.Y, near}, viewport))
+	mid := inv.TransformPoint(c.ScreenToNDC(&Vec3{screen.X, screen.Y, 0.5}, viewport))
+	return origin, mid.Subtract(origin).Normalize()
+}
+
+/*
+Project maps a world point to screen coordinates using OpenGL conventions,
+like gluProject.
+*/
+func Project(world *Vec3, view, proj *Mat4, viewport Vec4) *Vec3 {
+	return OpenGLClipSpace.Project(world, view, proj, viewport)
+}
+
+func Unproject(screen *Vec3, view, proj *Mat4, viewport Vec4) *Vec3 {
+	return OpenGLClipSpace.Unproject(screen, view, proj, viewport)
+}
+
+func ScreenPointToRay(screen *Vec2, view, proj *Mat4, viewport Vec4) (origin, direction *Vec3) {
+	return OpenGLClipSpace.ScreenPointToRay(screen, view, proj, viewport)
+}
@@ -0,0 +1,48 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestProjectUnproject(t *testing.T) {
+	viewport := mathg.Vec4{10., 20., 800., 600.}
+	eye := &mathg.Vec3{1., 2., 8.}
+	world := &mathg.Vec3{0.5, -0.3, -2.}
+	for _, clip := range []mathg.ClipSpace{
+		mathg.OpenGLClipSpace,
+		mathg.Direct3DClipSpace,
+		{Depth: mathg.DepthZeroToOne, ReversedZ: true, InfiniteFar: true},
+	} {
+		view := clip.LookAt(eye, &mathg.Vec3{}, &mathg.Vec3{0., 1., 0.})
+		proj := clip.Perspective(1., 800./600., 0.1, 100.)
+		s := clip.Project(world, view, proj, viewport)
+		if s.Z < 0. || s.Z > 1. {
+			t.Fatalf("%+v screen depth = %f", clip, s.Z)
+		}
+		vp := clip.Viewport(viewport).Multiply(proj).Multiply(view)
+		if !vec3NearlyEqual(vp.TransformPoint(world), s) {
+			t.Fatalf("%+v Viewport matrix disagrees with Project", clip)
+		}
+		if p := clip.Unproject(s, view, proj, viewport); !vec3NearlyEqual(p, world) {
+			t.Fatalf("%+v Unproject = %v, want %v", clip, p, world)
+		}
+		origin, dir := clip.ScreenPointToRay(&mathg.Vec2{s.X, s.Y}, view, proj, viewport)
+		// The ray passes through the projected point.
+		toWorld := world.Subtract(origin)
+		if !vec3NearlyEqual(toWorld.Cross(dir), &mathg.Vec3{}) || toWorld.Dot(dir) <= 0. {
+			t.Fatalf("%+v ray %v %v misses %v", clip, origin, dir, world)
+		}
+	}
+}
+
+func TestScreenPointToRayCenter(t *testing.T) {
+	viewport := mathg.Vec4{0., 0., 640., 480.}
+	view := (&mathg.Vec3{0., 0., 5.}).LookAt(&mathg.Vec3{}, &mathg.Vec3{0., 1., 0.})
+	proj := mathg.OpenGLClipSpace.Perspective(1., 640./480., 1., 50.)
+	origin, dir := mathg.ScreenPointToRay(&mathg.Vec2{320., 240.}, view, proj, viewport)
+	if !vec3NearlyEqual(origin, &mathg.Vec3{0., 0., 4.}) || !vec3NearlyEqual(dir, &mathg.Vec3{0., 0., -1.}) {
+		t.Fatalf("center ray = %v %v", origin, dir)
+	}
+}
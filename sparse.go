@@ -0,0 +1,281 @@
+package mathg
+
+import (
+	"math"
+	"sort"
+)
+
+/*
+SparseMatrix is a compressed sparse row matrix. The non zero values of row i
+are Values[RowPtr[i]:RowPtr[i+1]], in the columns given by the same range of
+ColIndex, sorted by column.
+*/
+type SparseMatrix struct {
+	Rows     int
+	Cols     int
+	RowPtr   []int
+	ColIndex []int
+	Values   []float64
+}
+
+type sparseEntry struct {
+	row   int
+	col   int
+	value float64
+}
+
+/*
+SparseBuilder collects entries in any order and builds a SparseMatrix.
+Entries added more than once at the same position are summed, which is how
+finite element and mesh stencils are usually assembled.
+*/
+type SparseBuilder struct {
+	rows    int
+	cols    int
+	entries []sparseEntry
+}
+
+func NewSparseBuilder(rows, cols int) *SparseBuilder {
+	return &SparseBuilder{rows: rows, cols: cols}
+}
+
+func (b *SparseBuilder) Add(row, col int, value float64) {
+	if row < 0 || row >= b.rows || col < 0 || col >= b.cols {
+		panic("mathg: SparseBuilder.Add index out of range")
+	}
+	b.entries = append(b.entries, sparseEntry{row, col, value})
+}
+
+func (b *SparseBuilder) Build() *SparseMatrix {
+	e := make([]sparseEntry, len(b.entries))
+	copy(e, b.entries)
+	sort.Slice(e, func(i, j int) bool {
+		if e[i].row != e[j].row {
+			return e[i].row < e[j].row
+		}
+		return e[i].col < e[j].col
+	})
+	m := &SparseMatrix{Rows: b.rows, Cols: b.cols, RowPtr: make([]int, b.rows+1)}
+	for i := 0; i < len(e); i++ {
+		n := len(m.Values)
+		if n > 0 && i > 0 && e[i].row == e[i-1].row && e[i].col == e[i-1].col {
+			m.Values[n-1] += e[i].value
+			continue
+		}
+		m.ColIndex = append(m.ColIndex, e[i].col)
+		m.Values = append(m.Values, e[i].value)
+		m.RowPtr[e[i].row+1] = len(m.Values)
+	}
+	for i := 1; i <= m.Rows; i++ {
+		if m.RowPtr[i] < m.RowPtr[i-1] {
+			m.RowPtr[i] = m.RowPtr[i-1]
+		}
+	}
+	return m
+}
+
+func (m *SparseMatrix) NonZeros() int {
+	return len(m.Values)
+}
+
+func (m *SparseMatrix) At(i, j int) float64 {
+	cols := m.ColIndex[m.RowPtr[i]:m.RowPtr[i+1]]
+	k := sort.SearchInts(cols, j)
+	if k < len(cols) && cols[k] == j {
+		return m.Values[m.RowPtr[i]+k]
+	}
+	return 0.
+}
+
+func (m *SparseMatrix) Diagonal() []float64 {
+	n := m.Rows
+	if m.Cols < n {
+		n = m.Cols
+	}
+	d := make([]float64, n)
+	for i := range d {
+		d[i] = m.At(i, i)
+	}
+	return d
+}
+
+func (m *SparseMatrix) MultiplyVec(x []float64) []float64 {
+	if len(x) != m.Cols {
+		panic("mathg: SparseMatrix.MultiplyVec dimension mismatch")
+	}
+	r := make([]float64, m.Rows)
+	for i := 0; i < m.Rows; i++ {
+		s := 0.
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			s += m.Values[k] * x[m.ColIndex[k]]
+		}
+		r[i] = s
+	}
+	return r
+}
+
+/*
+MultiplyVec3 applies the matrix to each coordinate of x independently, as
+used for per vertex positions or forces.
+*/
+func (m *SparseMatrix) MultiplyVec3(x []Vec3) []Vec3 {
+	if len(x) != m.Cols {
+		panic("mathg: SparseMatrix.MultiplyVec3 dimension mismatch")
+	}
+	r := make([]Vec3, m.Rows)
+	for i := 0; i < m.Rows; i++ {
+		for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+			v := &x[m.ColIndex[k]]
+			a := m.Values[k]
+			r[i].X += a * v.X
+			r[i].Y += a * v.Y
+			r[i].Z += a * v.Z
+		}
+	}
+	return r
+}
+
+/*
+SolverResult reports how an iterative solve ended. Residual is the final
+residual norm relative to the norm of the right hand side.
+*/
+type SolverResult struct {
+	Iterations int
+	Residual   float64
+	Converged  bool
+}
+
+func sliceDot(a, b []float64) float64 {
+	s := 0.
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+func initialGuess(x0 []float64, n int) []float64 {
+	x := make([]float64, n)
+	if x0 != nil {
+		if len(x0) != n {
+			panic("mathg: initial guess dimension mismatch")
+		}
+		copy(x, x0)
+	}
+	return x
+}
+
+func (m *SparseMatrix) residual(b, x []float64) []float64 {
+	r := m.MultiplyVec(x)
+	for i := range r {
+		r[i] = b[i] - r[i]
+	}
+	return r
+}
+
+/*
+ConjugateGradient solves A * x = b for a symmetric positive definite A using
+a Jacobi (diagonal) preconditioner. x0 may be nil to start from zero. The
+solve stops once the relative residual is at most tol or after maxIter
+iterations.
+*/
+func (m *SparseMatrix) ConjugateGradient(b, x0 []float64, tol float64, maxIter int) ([]float64, SolverResult) {
+	n := m.Rows
+	if m.Cols != n || len(b) != n {
+		panic("mathg: SparseMatrix.ConjugateGradient dimension mismatch")
+	}
+	x := initialGuess(x0, n)
+	bn := math.Sqrt(sliceDot(b, b))
+	if bn == 0. {
+		return make([]float64, n), SolverResult{0, 0., true}
+	}
+	inv := m.Diagonal()
+	for i, d := range inv {
+		if d != 0. {
+			inv[i] = 1. / d
+		} else {
+			inv[i] = 1.
+		}
+	}
+	r := m.residual(b, x)
+	z := make([]float64, n)
+	for i := range z {
+		z[i] = inv[i] * r[i]
+	}
+	p := make([]float64, n)
+	copy(p, z)
+	rz := sliceDot(r, z)
+	res := math.Sqrt(sliceDot(r, r)) / bn
+	for it := 0; it < maxIter; it++ {
+		if res <= tol {
+			return x, SolverResult{it, res, true}
+		}
+		ap := m.MultiplyVec(p)
+		pap := sliceDot(p, ap)
+		if pap <= 0. {
+			return x, SolverResult{it, res, false}
+		}
+		alpha := rz / pap
+		for i := range x {
+			x[i] += alpha * p[i]
+			r[i] -= alpha * ap[i]
+			z[i] = inv[i] * r[i]
+		}
+		res = math.Sqrt(sliceDot(r, r)) / bn
+		rzNew := sliceDot(r, z)
+		beta := rzNew / rz
+		rz = rzNew
+		for i := range p {
+			p[i] = z[i] + beta*p[i]
+		}
+	}
+	return x, SolverResult{maxIter, res, res <= tol}
+}
+
+/*
+SOR solves A * x = b with successive over-relaxation. omega of 1 gives
+Gauss-Seidel; values between 1 and 2 usually converge faster for the
+diagonally dominant systems it is meant for. x0 may be nil to start from
+zero.
+*/
+func (m *SparseMatrix) SOR(b, x0 []float64, omega, tol float64, maxIter int) ([]float64, SolverResult) {
+	n := m.Rows
+	if m.Cols != n || len(b) != n {
+		panic("mathg: SparseMatrix.SOR dimension mismatch")
+	}
+	x := initialGuess(x0, n)
+	bn := math.Sqrt(sliceDot(b, b))
+	if bn == 0. {
+		return make([]float64, n), SolverResult{0, 0., true}
+	}
+	res := 0.
+	for it := 0; it < maxIter; it++ {
+		r := m.residual(b, x)
+		res = math.Sqrt(sliceDot(r, r)) / bn
+		if res <= tol {
+			return x, SolverResult{it, res, true}
+		}
+		for i := 0; i < n; i++ {
+			s := b[i]
+			d := 0.
+			for k := m.RowPtr[i]; k < m.RowPtr[i+1]; k++ {
+				j := m.ColIndex[k]
+				if j == i {
+					d = m.Values[k]
+				} else {
+					s -= m.Values[k] * x[j]
+				}
+			}
+			if d == 0. {
+				return x, SolverResult{it, res, false}
+			}
+			x[i] += omega * (s/d - x[i])
+		}
+	}
+	r := m.residual(b, x)
+	res = math.Sqrt(sliceDot(r, r)) / bn
+	return x, SolverResult{maxIter, res, res <= tol}
+}
+
+func (m *SparseMatrix) GaussSeidel(b, x0 []float64, tol float64, maxIter int) ([]float64, SolverResult) {
+	return m.SOR(b, x0, 1., tol, maxIter)
+}
@@ -0,0 +1,62 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+// 1D Laplacian with a diagonal shift, symmetric positive definite.
+func laplacian(n int) *mathg.SparseMatrix {
+	b := mathg.NewSparseBuilder(n, n)
+	for i := 0; i < n; i++ {
+		b.Add(i, i, 2.)
+		b.Add(i, i, 0.5)
+		if i > 0 {
+			b.Add(i, i-1, -1.)
+		}
+		if i < n-1 {
+			b.Add(i, i+1, -1.)
+		}
+	}
+	return b.Build()
+}
+
+func TestSparseBuilder(t *testing.T) {
+	m := laplacian(4)
+	if m.NonZeros() != 10 || m.At(1, 1) != 2.5 || m.At(1, 2) != -1. || m.At(0, 3) != 0. {
+		t.Fatalf("Unexpected sparse matrix %v", *m)
+	}
+	v := m.MultiplyVec3([]mathg.Vec3{{1., 0., 0.}, {0., 1., 0.}, {0., 0., 1.}, {1., 1., 1.}})
+	if !vec3NearlyEqual(&v[1], &mathg.Vec3{-1., 2.5, -1.}) {
+		t.Fatalf("MultiplyVec3 = %v", v[1])
+	}
+}
+
+func TestSparseConjugateGradient(t *testing.T) {
+	m := laplacian(50)
+	want := make([]float64, 50)
+	for i := range want {
+		want[i] = float64(i%7) - 3.
+	}
+	x, res := m.ConjugateGradient(m.MultiplyVec(want), nil, 1e-12, 200)
+	if !res.Converged || !slicesNearlyEqual(x, want) {
+		t.Fatalf("ConjugateGradient = %v after %d iterations", res, res.Iterations)
+	}
+}
+
+func TestSparseGaussSeidel(t *testing.T) {
+	m := laplacian(20)
+	want := make([]float64, 20)
+	for i := range want {
+		want[i] = float64(i) * 0.1
+	}
+	x, res := m.GaussSeidel(m.MultiplyVec(want), nil, 1e-12, 1000)
+	if !res.Converged || !slicesNearlyEqual(x, want) {
+		t.Fatalf("GaussSeidel = %v", res)
+	}
+	_, res = m.SOR(m.MultiplyVec(want), nil, 1.2, 1e-12, 2)
+	if res.Converged || res.Iterations != 2 {
+		t.Fatalf("SOR should stop unconverged at the iteration limit, got %v", res)
+	}
+}
@@ -0,0 +1,153 @@
+package mathg
+
+import (
+	"math"
+	"sort"
+)
+
+/*
+SquadControlPoint returns the inner control point for q given its
+neighbouring keys, as used by Squad. The neighbours are flipped onto the
+same hemisphere as q so the curve follows the shortest path.
+*/
+func (q *Quaternion) SquadControlPoint(prev, next *Quaternion) *Quaternion {
+	if q.Dot(prev) < 0. {
+		prev = prev.Negative()
+	}
+	if q.Dot(next) < 0. {
+		next = next.Negative()
+	}
+	inv := q.Inverse()
+	l0 := inv.Multiply(prev).Log()
+	l1 := inv.Multiply(next).Log()
+	sum := &Quaternion{l0.X + l1.X, l0.Y + l1.Y, l0.Z + l1.Z, l0.W + l1.W}
+	return q.Multiply(sum.MultiplyScalar(-0.25).Exp())
+}
+
+/*
+Squad interpolates between q0 and q1 using the control points a and b,
+giving a C1 continuous curve across consecutive segments of equal duration.
+QuaternionSpline adjusts the control points for unevenly spaced keys.
+*/
+func Squad(q0, q1, a, b *Quaternion, t float64) *Quaternion {
+	return slerpLong(q0.Slerp(q1, t), slerpLong(a, b, t), 2.*t*(1.-t))
+}
+
+// Slerp without the shortest path flip, as required by the inner Squad terms.
+func slerpLong(q, q1 *Quaternion, percent float64) *Quaternion {
+	d := Clamp(q.Dot(q1), -1., 1.)
+	if math.Abs(d) > 0.9995 {
+		return q.Lerp(q1, percent).Normalize()
+	}
+	theta := math.Acos(d)
+	sin_theta := math.Sin(theta)
+	f0 := math.Sin((1.0-percent)*theta) / sin_theta
+	f1 := math.Sin(percent*theta) / sin_theta
+	return &Quaternion{
+		q.X*f0 + q1.X*f1,
+		q.Y*f0 + q1.Y*f1,
+		q.Z*f0 + q1.Z*f1,
+		q.W*f0 + q1.W*f1,
+	}
+}
+
+type QuaternionKey struct {
+	Time  float64
+	Value Quaternion
+}
+
+type QuaternionSpline struct {
+	keys []QuaternionKey
+	// Control points on the incoming and outgoing side of each key.
+	in, out []Quaternion
+}
+
+/*
+squadControlPoints returns the incoming and outgoing control points of q
+for the neighbouring keys prev and next, dtPrev before and dtNext after it.
+They give the same angular velocity on both sides of q however the keys
+are spaced, and both equal SquadControlPoint when dtPrev equals dtNext.
+*/
+func squadControlPoints(prev, q, next *Quaternion, dtPrev, dtNext float64) (in, out *Quaternion) {
+	if dtPrev+dtNext <= 0. {
+		c := q.SquadControlPoint(prev, next)
+		return c, c
+	}
+	inv := q.Inverse()
+	lp := inv.Multiply(prev).Log()
+	ln := inv.Multiply(next).Log()
+	// Tangent per unit time, exact for a constant angular velocity.
+	s := 1. / (dtPrev + dtNext)
+	t := &Vec3{(ln.X - lp.X) * s, (ln.Y - lp.Y) * s, (ln.Z - lp.Z) * s}
+	a := &Quaternion{0.5 * (dtNext*t.X - ln.X), 0.5 * (dtNext*t.Y - ln.Y), 0.5 * (dtNext*t.Z - ln.Z), 0.}
+	b := &Quaternion{-0.5 * (dtPrev*t.X + lp.X), -0.5 * (dtPrev*t.Y + lp.Y), -0.5 * (dtPrev*t.Z + lp.Z), 0.}
+	return q.Multiply(b.Exp()), q.Multiply(a.Exp())
+}
+
+/*
+NewQuaternionSpline sorts the keys by time, normalizes them and aligns each
+key with the hemisphere of the one before it. The control points account
+for uneven key spacing, so the angular velocity is continuous at every
+interior key.
+*/
+func NewQuaternionSpline(keys []QuaternionKey) *QuaternionSpline {
+	s := &QuaternionSpline{keys: make([]QuaternionKey, len(keys))}
+	copy(s.keys, keys)
+	sort.SliceStable(s.keys, func(i, j int) bool { return s.keys[i].Time < s.keys[j].Time })
+	for i := range s.keys {
+		q := s.keys[i].Value.Normalize()
+		if i > 0 && q.Dot(&s.keys[i-1].Value) < 0. {
+			q = q.Negative()
+		}
+		s.keys[i].Value = *q
+	}
+	n := len(s.keys)
+	s.in = make([]Quaternion, n)
+	s.out = make([]Quaternion, n)
+	for i := range s.keys {
+		q := &s.keys[i].Value
+		if i == 0 || i == n-1 {
+			prev, next := q, q
+			if i > 0 {
+				prev = &s.keys[i-1].Value
+			}
+			if i < n-1 {
+				next = &s.keys[i+1].Value
+			}
+			c := q.SquadControlPoint(prev, next)
+			s.in[i], s.out[i] = *c, *c
+			continue
+		}
+		k0, k1 := &s.keys[i-1], &s.keys[i+1]
+		in, out := squadControlPoints(&k0.Value, q, &k1.Value, s.keys[i].Time-k0.Time, k1.Time-s.keys[i].Time)
+		s.in[i], s.out[i] = *in, *out
+	}
+	return s
+}
+
+func (s *QuaternionSpline) Keys() []QuaternionKey {
+	return s.keys
+}
+
+/*
+Evaluate returns the rotation at the given time. Times outside the key range
+are clamped to the first or last key. An empty spline returns the identity.
+*/
+func (s *QuaternionSpline) Evaluate(time float64) *Quaternion {
+	n := len(s.keys)
+	if n == 0 {
+		return &Quaternion{0., 0., 0., 1.}
+	}
+	if time <= s.keys[0].Time {
+		q := s.keys[0].Value
+		return &q
+	}
+	if time >= s.keys[n-1].Time {
+		q := s.keys[n-1].Value
+		return &q
+	}
+	i := sort.Search(n, func(i int) bool { return s.keys[i].Time > time }) - 1
+	k0, k1 := &s.keys[i], &s.keys[i+1]
+	t := (time - k0.Time) / (k1.Time - k0.Time)
+	return Squad(&k0.Value, &k1.Value, &s.out[i], &s.in[i+1], t).Normalize()
+}
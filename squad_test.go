@@ -0,0 +1,79 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func quaternionNearlyEqual(a, b *mathg.Quaternion) bool {
+	return mathg.NearlyEqual(a.X, b.X, tolerance) && mathg.NearlyEqual(a.Y, b.Y, tolerance) &&
+		mathg.NearlyEqual(a.Z, b.Z, tolerance) && mathg.NearlyEqual(a.W, b.W, tolerance)
+}
+
+func TestQuaternionExpLog(t *testing.T) {
+	for _, q := range []*mathg.Quaternion{
+		(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.2, -0.4, 0.9}, mathg.EulerXYZ),
+		{0., 0., 0., -1.},
+		{0., 0., 0., -2.},
+		{1e-20, 0., 0., -1.},
+		{0., 0., 0., 1.},
+	} {
+		r := q.Log().Exp()
+		if !quaternionNearlyEqual(q, r) {
+			t.Fatalf("Exp(Log(q)) = %v, want %v", *r, *q)
+		}
+	}
+}
+
+func TestQuaternionSplineKeys(t *testing.T) {
+	keys := []mathg.QuaternionKey{
+		{Time: 0., Value: *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0., 0., 0.}, mathg.EulerXYZ)},
+		{Time: 1., Value: *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.5, 0.1, 0.}, mathg.EulerXYZ)},
+		{Time: 2.5, Value: *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.9, 0.8, -0.3}, mathg.EulerXYZ)},
+		{Time: 3., Value: *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.2, 1.4, 0.3}, mathg.EulerXYZ)},
+	}
+	s := mathg.NewQuaternionSpline(keys)
+	for _, k := range s.Keys() {
+		q := s.Evaluate(k.Time)
+		if !quaternionNearlyEqual(q, &k.Value) {
+			t.Fatalf("Spline at %f = %v, want %v", k.Time, *q, k.Value)
+		}
+	}
+	q := s.Evaluate(1.75)
+	if !mathg.NearlyEqual(q.Magnitude(), 1., tolerance) {
+		t.Fatalf("Spline returned a non unit quaternion %v", *q)
+	}
+}
+
+func TestQuaternionSplineC1(t *testing.T) {
+	// Evenly and unevenly spaced keys: the angular velocity must not jump at
+	// the interior keys.
+	angles := []*mathg.Vec3{{0., 0., 0.}, {0.5, 0.1, 0.}, {0.9, 0.8, -0.3}, {0.2, 1.4, 0.3}}
+	for _, times := range [][]float64{{0., 1., 2., 3.}, {0., 1., 2.5, 3.}} {
+		keys := make([]mathg.QuaternionKey, len(times))
+		for i := range keys {
+			keys[i] = mathg.QuaternionKey{Time: times[i], Value: *(&mathg.Quaternion{}).FromEuler(angles[i], mathg.EulerXYZ)}
+		}
+		s := mathg.NewQuaternionSpline(keys)
+		h := 1e-6
+		for _, k := range times[1 : len(times)-1] {
+			before := mathg.AngularVelocity(s.Evaluate(k-h), s.Evaluate(k), h)
+			after := mathg.AngularVelocity(s.Evaluate(k), s.Evaluate(k+h), h)
+			if d := before.Subtract(after).Magnitude(); d > 1e-4 {
+				t.Fatalf("keys at %v: angular velocity jumps by %g at %f, %v before and %v after", times, d, k, *before, *after)
+			}
+		}
+	}
+	// With even spacing the spline is plain SQUAD.
+	q := make([]*mathg.Quaternion, len(angles))
+	keys := make([]mathg.QuaternionKey, len(angles))
+	for i, a := range angles {
+		q[i] = (&mathg.Quaternion{}).FromEuler(a, mathg.EulerXYZ)
+		keys[i] = mathg.QuaternionKey{Time: float64(i), Value: *q[i]}
+	}
+	want := mathg.Squad(q[1], q[2], q[1].SquadControlPoint(q[0], q[2]), q[2].SquadControlPoint(q[1], q[3]), 0.3).Normalize()
+	if got := mathg.NewQuaternionSpline(keys).Evaluate(1.3); !quaternionNearlyEqual(got, want) {
+		t.Fatalf("evenly spaced spline = %v, Squad = %v", *got, *want)
+	}
+}
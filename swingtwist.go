@@ -0,0 +1,74 @@
+package mathg
+
+import "math"
+
+/*
+SwingTwist splits q into a twist about axis followed by a swing that moves
+the axis, so that q = swing * twist. When q turns the axis by 180 degrees
+the twist is undefined and returned as the identity.
+*/
+func (q *Quaternion) SwingTwist(axis *Vec3) (swing, twist *Quaternion) {
+	n := axis.Normalize()
+	d := q.X*n.X + q.Y*n.Y + q.Z*n.Z
+	twist = &Quaternion{n.X * d, n.Y * d, n.Z * d, q.W}
+	if twist.LengthSquared() < epsilon {
+		twist = &Quaternion{0., 0., 0., 1.}
+	} else {
+		twist = twist.Normalize()
+	}
+	swing = q.Multiply(twist.Conjugate())
+	return swing, twist
+}
+
+// Signed angle of a twist about axis, in (-pi, pi].
+func twistAngle(twist *Quaternion, axis *Vec3) float64 {
+	n := axis.Normalize()
+	d := twist.X*n.X + twist.Y*n.Y + twist.Z*n.Z
+	return wrapAngle(2. * math.Atan2(d, twist.W))
+}
+
+func twistFromAngle(axis *Vec3, angle float64) *Quaternion {
+	n := axis.Normalize()
+	return n.ToVec4().ToQuaternionFromAxisAngle(angle)
+}
+
+/*
+LimitCone clamps the swing of q away from axis to at most maxAngle radians,
+keeping the twist about axis unchanged.
+*/
+func (q *Quaternion) LimitCone(axis *Vec3, maxAngle float64) *Quaternion {
+	swing, twist := q.SwingTwist(axis)
+	if swing.W < 0. {
+		swing = swing.Negative()
+	}
+	v := &Vec3{swing.X, swing.Y, swing.Z}
+	l := v.Magnitude()
+	angle := 2. * math.Atan2(l, swing.W)
+	if angle <= maxAngle || l < epsilon {
+		return q
+	}
+	swing = v.DivideScalar(l).ToVec4().ToQuaternionFromAxisAngle(maxAngle)
+	return swing.Multiply(twist)
+}
+
+/*
+LimitTwist clamps the twist of q about axis to the range [min, max] radians,
+keeping the swing unchanged.
+*/
+func (q *Quaternion) LimitTwist(axis *Vec3, min, max float64) *Quaternion {
+	swing, twist := q.SwingTwist(axis)
+	angle := twistAngle(twist, axis)
+	if angle >= min && angle <= max {
+		return q
+	}
+	return swing.Multiply(twistFromAngle(axis, Clamp(angle, min, max)))
+}
+
+/*
+LimitHinge discards any swing and keeps only the twist of q about axis,
+clamped to the range [min, max] radians.
+*/
+func (q *Quaternion) LimitHinge(axis *Vec3, min, max float64) *Quaternion {
+	_, twist := q.SwingTwist(axis)
+	return twistFromAngle(axis, Clamp(twistAngle(twist, axis), min, max))
+}
@@ -0,0 +1,66 @@
+package mathg_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func axisAngle(axis *mathg.Vec3, angle float64) *mathg.Quaternion {
+	return axis.Normalize().ToVec4().ToQuaternionFromAxisAngle(angle)
+}
+
+func TestSwingTwist(t *testing.T) {
+	axis := &mathg.Vec3{0.3, -0.5, 0.8}
+	q := (&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.7, -1.1, 0.4}, mathg.EulerXYZ)
+	swing, twist := q.SwingTwist(axis)
+	if !quaternionNearlyEqual(swing.Multiply(twist), q) {
+		t.Fatalf("swing * twist = %v, want %v", *swing.Multiply(twist), *q)
+	}
+	// The twist turns about the axis and the swing about a perpendicular.
+	n := axis.Normalize()
+	if c := (&mathg.Vec3{twist.X, twist.Y, twist.Z}).Cross(n); !mathg.NearlyEqual(c.Magnitude(), 0., tolerance) {
+		t.Fatal("twist moves the axis")
+	}
+	if d := (&mathg.Vec3{swing.X, swing.Y, swing.Z}).Dot(n); !mathg.NearlyEqual(d, 0., tolerance) {
+		t.Fatalf("swing has a component %f about the axis", d)
+	}
+}
+
+func TestSwingTwistHalfTurn(t *testing.T) {
+	// A half turn about X flips the Z axis, leaving the twist undefined.
+	q := axisAngle(&mathg.Vec3{1., 0., 0.}, math.Pi)
+	swing, twist := q.SwingTwist(&mathg.Vec3{0., 0., 1.})
+	if !quaternionNearlyEqual(twist, &mathg.Quaternion{0., 0., 0., 1.}) || !quaternionNearlyEqual(swing, q) {
+		t.Fatalf("half turn split into %v, %v", *swing, *twist)
+	}
+}
+
+func TestSwingTwistLimits(t *testing.T) {
+	z := &mathg.Vec3{0., 0., 1.}
+	swing := axisAngle(&mathg.Vec3{1., 0., 0.}, 1.)
+	twist := axisAngle(z, 1.2)
+	q := swing.Multiply(twist)
+
+	s, tw := q.LimitCone(z, 0.5).SwingTwist(z)
+	if a := 2. * math.Acos(math.Abs(s.W)); !mathg.NearlyEqual(a, 0.5, tolerance) || !quaternionNearlyEqual(tw, twist) {
+		t.Fatalf("LimitCone swing angle = %f", a)
+	}
+	if r := q.LimitCone(z, 2.); !quaternionNearlyEqual(r, q) {
+		t.Fatal("LimitCone changed a rotation inside the cone")
+	}
+
+	s, tw = q.LimitTwist(z, -0.5, 0.5).SwingTwist(z)
+	if !quaternionNearlyEqual(s, swing) || !quaternionNearlyEqual(tw, axisAngle(z, 0.5)) {
+		t.Fatalf("LimitTwist = %v, %v", *s, *tw)
+	}
+
+	h := q.LimitHinge(z, -0.3, 0.8)
+	if !quaternionNearlyEqual(h, axisAngle(z, 0.8)) {
+		t.Fatalf("LimitHinge = %v", *h)
+	}
+	if h := axisAngle(z, -1.).LimitHinge(z, -0.3, 0.8); !quaternionNearlyEqual(h, axisAngle(z, -0.3)) {
+		t.Fatalf("LimitHinge lower bound = %v", *h)
+	}
+}
@@ -0,0 +1,112 @@
+package mathg
+
+import "math"
+
+// Tolerance used by Decompose to reject shear and degenerate scale.
+const decomposeEpsilon float64 = 1e-6
+
+/*
+Transform stores a scale, then rotation, then translation. Its matrix is
+Translation * Rotation * Scale.
+*/
+type Transform struct {
+	Translation Vec3
+	Rotation    Quaternion
+	Scale       Vec3
+}
+
+func (t *Transform) Identity() *Transform {
+	return &Transform{Vec3{0., 0., 0.}, Quaternion{0., 0., 0., 1.}, Vec3{1., 1., 1.}}
+}
+
+func (t *Transform) ToMat4() *Mat4 {
+	r := t.Rotation.RotationMatrix()
+	s := &t.Scale
+	return &Mat4{
+		r.M11 * s.X, r.M21 * s.X, r.M31 * s.X, 0.,
+		r.M12 * s.Y, r.M22 * s.Y, r.M32 * s.Y, 0.,
+		r.M13 * s.Z, r.M23 * s.Z, r.M33 * s.Z, 0.,
+		t.Translation.X, t.Translation.Y, t.Translation.Z, 1.,
+	}
+}
+
+func (t *Transform) TransformPoint(v *Vec3) *Vec3 {
+	return t.TransformDirection(v).Add(&t.Translation)
+}
+
+/*
+TransformDirection applies scale and rotation but not translation.
+*/
+func (t *Transform) TransformDirection(v *Vec3) *Vec3 {
+	return t.Rotation.RotateVec3(v.Multiply(&t.Scale))
+}
+
+/*
+Compose returns the transform that applies t1 first and then t. Non-uniform
+scale on t combined with rotation on t1 produces shear, which a Transform
+cannot hold; in that case the result only approximates t * t1.
+*/
+func (t *Transform) Compose(t1 *Transform) *Transform {
+	return &Transform{
+		*t.TransformPoint(&t1.Translation),
+		*t.Rotation.Multiply(&t1.Rotation).Normalize(),
+		*t.Scale.Multiply(&t1.Scale),
+	}
+}
+
+/*
+Inverse is exact for uniform scale. With non-uniform scale and rotation the
+true inverse contains shear and is only approximated.
+*/
+func (t *Transform) Inverse() *Transform {
+	s := &Vec3{1. / t.Scale.X, 1. / t.Scale.Y, 1. / t.Scale.Z}
+	r := t.Rotation.Conjugate()
+	tr := r.RotateVec3(t.Translation.Negative()).Multiply(s)
+	return &Transform{*tr, *r, *s}
+}
+
+func (t *Transform) Lerp(t1 *Transform, percent float64) *Transform {
+	return &Transform{
+		*t.Translation.Lerp(&t1.Translation, percent),
+		*t.Rotation.Slerp(&t1.Rotation, percent).Normalize(),
+		*t.Scale.Lerp(&t1.Scale, percent),
+	}
+}
+
+/*
+Decompose splits an affine matrix into translation, rotation and scale. A
+reflection is returned as a negative X scale. ok is false when the matrix
+has a projective row, a zero scale axis or shear; the returned parts are
+then only a best effort.
+*/
+func (m *Mat4) Decompose() (t *Vec3, r *Quaternion, s *Vec3, ok bool) {
+	ok = true
+	t = &Vec3{m.M14, m.M24, m.M34}
+	x := &Vec3{m.M11, m.M21, m.M31}
+	y := &Vec3{m.M12, m.M22, m.M32}
+	z := &Vec3{m.M13, m.M23, m.M33}
+	s = &Vec3{x.Magnitude(), y.Magnitude(), z.Magnitude()}
+	if math.Abs(m.M41) > decomposeEpsilon || math.Abs(m.M42) > decomposeEpsilon ||
+		math.Abs(m.M43) > decomposeEpsilon || math.Abs(m.M44-1.) > decomposeEpsilon {
+		ok = false
+	}
+	if s.X < decomposeEpsilon || s.Y < decomposeEpsilon || s.Z < decomposeEpsilon {
+		return t, &Quaternion{0., 0., 0., 1.}, s, false
+	}
+	if x.Dot(y.Cross(z)) < 0. {
+		s.X = -s.X
+	}
+	x = x.DivideScalar(s.X)
+	y = y.DivideScalar(s.Y)
+	z = z.DivideScalar(s.Z)
+	if math.Abs(x.Dot(y)) > decomposeEpsilon || math.Abs(x.Dot(z)) > decomposeEpsilon ||
+		math.Abs(y.Dot(z)) > decomposeEpsilon {
+		ok = false
+	}
+	r = QuaternionFromMat3(&Mat3{
+		x.X, x.Y, x.Z,
+		y.X, y.Y, y.Z,
+		z.X, z.Y, z.Z,
+	}).Normalize()
+	return t, r, s, ok
+}
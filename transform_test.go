@@ -0,0 +1,64 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func mat4NearlyEqual(a, b *mathg.Mat4) bool {
+	x := []float64{a.M11, a.M21, a.M31, a.M41, a.M12, a.M22, a.M32, a.M42, a.M13, a.M23, a.M33, a.M43, a.M14, a.M24, a.M34, a.M44}
+	y := []float64{b.M11, b.M21, b.M31, b.M41, b.M12, b.M22, b.M32, b.M42, b.M13, b.M23, b.M33, b.M43, b.M14, b.M24, b.M34, b.M44}
+	for i := range x {
+		if !mathg.NearlyEqual(x[i], y[i], tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTransformDecompose(t *testing.T) {
+	tr := &mathg.Transform{
+		Translation: mathg.Vec3{1., -2., 3.},
+		Rotation:    *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.3, 0.5, -1.2}, mathg.EulerXYZ),
+		Scale:       mathg.Vec3{-2., 0.5, 4.},
+	}
+	m := tr.ToMat4()
+	pos, rot, scale, ok := m.Decompose()
+	if !ok {
+		t.Fatal("Decompose rejected a TRS matrix")
+	}
+	d := &mathg.Transform{Translation: *pos, Rotation: *rot, Scale: *scale}
+	if !mat4NearlyEqual(d.ToMat4(), m) {
+		t.Fatalf("Decompose = %v, %v, %v", *pos, *rot, *scale)
+	}
+}
+
+func TestTransformDecomposeShear(t *testing.T) {
+	m := (&mathg.Mat4{}).Identity()
+	m.M12 = 0.5
+	if _, _, _, ok := m.Decompose(); ok {
+		t.Fatal("Decompose accepted a sheared matrix")
+	}
+}
+
+func TestTransformComposeInverse(t *testing.T) {
+	a := &mathg.Transform{
+		Translation: mathg.Vec3{1., 2., 3.},
+		Rotation:    *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{0.1, 0.2, 0.3}, mathg.EulerXYZ),
+		Scale:       mathg.Vec3{2., 2., 2.},
+	}
+	b := &mathg.Transform{
+		Translation: mathg.Vec3{-1., 0., 4.},
+		Rotation:    *(&mathg.Quaternion{}).FromEuler(&mathg.Vec3{-0.6, 0.4, 1.1}, mathg.EulerXYZ),
+		Scale:       mathg.Vec3{1., 3., 0.5},
+	}
+	if !mat4NearlyEqual(a.Compose(b).ToMat4(), a.ToMat4().Multiply(b.ToMat4())) {
+		t.Fatal("Compose does not match the matrix product")
+	}
+	p := &mathg.Vec3{0.5, -1., 2.}
+	back := a.Inverse().TransformPoint(a.TransformPoint(p))
+	if !vec3NearlyEqual(back, p) {
+		t.Fatalf("Inverse round trip = %v", *back)
+	}
+}
@@ -113,7 +113,7 @@ func (v *Vec3) Cross(v1 *Vec3) *Vec3 {
 }
 
 func (v *Vec3) Dot(v1 *Vec3) float64 {
-	return v.X*v1.X + v.Y*v1.Y + v.X*v1.Z
+	return v.X*v1.X + v.Y*v1.Y + v.Z*v1.Z
 }
 
 func (v *Vec3) Magnitude() float64 {
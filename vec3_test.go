@@ -0,0 +1,17 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestVec3Dot(t *testing.T) {
+	v := &mathg.Vec3{1., 2., 3.}
+	if d := v.Dot(&mathg.Vec3{4., -5., 6.}); d != 12. {
+		t.Fatalf("Dot = %f, want 12", d)
+	}
+	if d := (&mathg.Vec3{0., 0., 2.}).Dot(&mathg.Vec3{0., 0., 3.}); d != 6. {
+		t.Fatalf("Dot of Z vectors = %f, want 6", d)
+	}
+}
@@ -44,7 +44,7 @@ func (v *Vec4) AddScalar(scalar float64) *Vec4 {
 }
 
 func (v *Vec4) Subtract(v1 *Vec4) *Vec4 {
-	return &Vec4{v.X - v1.X, v.Y - v1.Y, v.Z - v1.Z, v.W + v1.W}
+	return &Vec4{v.X - v1.X, v.Y - v1.Y, v.Z - v1.Z, v.W - v1.W}
 }
 
 func (v *Vec4) SubtractScalar(scalar float64) *Vec4 {
@@ -126,6 +126,10 @@ func (v *Vec4) Clamp(min *Vec4, max *Vec4) *Vec4 {
 	}
 }
 
+func (v *Vec4) Dot(v1 *Vec4) float64 {
+	return v.X*v1.X + v.Y*v1.Y + v.Z*v1.Z + v.W*v1.W
+}
+
 func (v *Vec4) Magnitude() float64 {
 	return math.Sqrt(math.Pow(v.X, 2) + math.Pow(v.Y, 2) + math.Pow(v.Z, 2) + math.Pow(v.W, 2))
 }
@@ -0,0 +1,14 @@
+package mathg_test
+
+import (
+	"testing"
+
+	"github.com/christopherfranklin/mathg"
+)
+
+func TestVec4Subtract(t *testing.T) {
+	v := (&mathg.Vec4{4., 3., 2., 1.}).Subtract(&mathg.Vec4{1., 1., 1., 1.})
+	if want := (&mathg.Vec4{3., 2., 1., 0.}); !v.IsEqual(want) {
+		t.Fatalf("Subtract = %v, want %v", *v, *want)
+	}
+}